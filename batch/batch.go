@@ -0,0 +1,286 @@
+// Package batch runs large offline workloads through provider batch APIs,
+// persisting job state locally so an interrupted run can be resumed.
+package batch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/requestkey"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+var (
+	// ErrStateMismatch is returned when the state file belongs to a different set of requests
+	ErrStateMismatch = errors.New("batch state does not match requests")
+	// ErrJobFailed is returned when a job ended without producing any results
+	ErrJobFailed = errors.New("batch job failed")
+)
+
+// State is the job state persisted between runs
+type State struct {
+	// Fingerprint identifies the submitted custom IDs and request contents
+	Fingerprint string                       `json:"fingerprint"`
+	Job         types.BatchJob               `json:"job"`
+	Results     map[string]types.BatchResult `json:"results,omitempty"`
+	UpdatedAt   time.Time                    `json:"updatedAt"`
+}
+
+// Runner submits a batch, polls it to completion and collects the results
+type Runner struct {
+	batcher      provider.Batcher
+	statePath    string
+	pollInterval time.Duration
+	onStatus     func(types.BatchJob)
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithPollInterval sets how often job status is checked (default one minute)
+func WithPollInterval(interval time.Duration) Option {
+	return func(r *Runner) {
+		r.pollInterval = interval
+	}
+}
+
+// WithStatusCallback is called with every polled job status
+func WithStatusCallback(fn func(types.BatchJob)) Option {
+	return func(r *Runner) {
+		r.onStatus = fn
+	}
+}
+
+// New creates a Runner that keeps its job state in statePath
+func New(batcher provider.Batcher, statePath string, opts ...Option) *Runner {
+	r := &Runner{
+		batcher:      batcher,
+		statePath:    statePath,
+		pollInterval: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run returns the results of requests keyed by custom ID. If the state file
+// holds a job for the same requests it is resumed instead of resubmitted, and
+// a completed job returns its stored results without contacting the provider.
+// Results of failed requests have Error set. A job that ended without any
+// output fails with ErrJobFailed and is resubmitted by the next Run. Requests
+// with other custom IDs or edited contents fail with ErrStateMismatch until
+// Reset is called.
+func (r *Runner) Run(ctx context.Context, requests []types.BatchRequest) (map[string]types.BatchResult, error) {
+	fingerprint, err := fingerprint(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("%w: %s", ErrStateMismatch, r.statePath)
+	}
+	if state != nil && state.Results != nil {
+		return state.Results, nil
+	}
+	if state != nil && failed(state.Job) {
+		state = nil
+	}
+
+	if state == nil {
+		job, err := r.batcher.SubmitBatch(ctx, requests)
+		if err != nil {
+			return nil, err
+		}
+		state = &State{Fingerprint: fingerprint, Job: job}
+		if err := r.save(state); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := r.wait(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if failed(job) {
+		return nil, fmt.Errorf("%w: %s %s%s", ErrJobFailed, job.ID, job.Status, jobErrors(job))
+	}
+
+	results, err := r.batcher.BatchResults(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Results = make(map[string]types.BatchResult, len(results))
+	for _, result := range results {
+		state.Results[result.CustomID] = result
+	}
+	// Requests that were never processed, e.g. when the job expired
+	for _, request := range requests {
+		if _, ok := state.Results[request.CustomID]; !ok {
+			state.Results[request.CustomID] = types.BatchResult{
+				CustomID: request.CustomID,
+				Error:    fmt.Sprintf("no result (batch %s%s)", job.Status, jobErrors(job)),
+			}
+		}
+	}
+	if err := r.save(state); err != nil {
+		return nil, err
+	}
+
+	return state.Results, nil
+}
+
+// Cancel cancels the job recorded in the state file
+func (r *Runner) Cancel(ctx context.Context) error {
+	state, err := r.load()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil
+	}
+
+	job, err := r.batcher.CancelBatch(ctx, state.Job.ID)
+	if err != nil {
+		return err
+	}
+	state.Job = job
+	return r.save(state)
+}
+
+// Reset removes the state file, so the next Run submits a new job
+func (r *Runner) Reset() error {
+	if err := os.Remove(r.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove batch state: %w", err)
+	}
+	return nil
+}
+
+// failed reports whether job ended without output or error files to read
+func failed(job types.BatchJob) bool {
+	return job.Status.Terminal() && job.Status != types.BatchStatusCompleted && job.OutputFileID == "" && job.ErrorFileID == ""
+}
+
+// jobErrors formats the job level errors reported by the provider
+func jobErrors(job types.BatchJob) string {
+	if len(job.Errors) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(job.Errors, "; ")
+}
+
+// wait polls until the job reaches a terminal status, saving each update
+func (r *Runner) wait(ctx context.Context, state *State) (types.BatchJob, error) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := r.batcher.BatchStatus(ctx, state.Job.ID)
+		if err != nil {
+			return types.BatchJob{}, err
+		}
+		if r.onStatus != nil {
+			r.onStatus(job)
+		}
+
+		if job.Status != state.Job.Status || job.Completed != state.Job.Completed {
+			state.Job = job
+			if err := r.save(state); err != nil {
+				return types.BatchJob{}, err
+			}
+		}
+		if job.Status.Terminal() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return types.BatchJob{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) load() (*State, error) {
+	data, err := os.ReadFile(r.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read batch state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode batch state: %w", err)
+	}
+	return &state, nil
+}
+
+// save writes the state atomically so a crash never leaves a partial file
+func (r *Runner) save(state *State) error {
+	state.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode batch state: %w", err)
+	}
+
+	dir := filepath.Dir(r.statePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".batch-*")
+	if err != nil {
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.statePath); err != nil {
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	return nil
+}
+
+// fingerprint hashes each custom ID with the request key of its context, so
+// editing a request is detected even when the IDs stay the same
+func fingerprint(requests []types.BatchRequest) (string, error) {
+	entries := make([]string, 0, len(requests))
+	for _, request := range requests {
+		key, err := requestkey.Key("", "", request.Context)
+		if err != nil {
+			return "", fmt.Errorf("fingerprint request %s: %w", request.CustomID, err)
+		}
+		entries = append(entries, request.CustomID+"\x00"+key)
+	}
+	sort.Strings(entries)
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		hash.Write([]byte(entry))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,164 @@
+package batch_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/batch"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// fakeBatcher finishes every job with the next scripted outcome
+type fakeBatcher struct {
+	outcomes  []types.BatchJob
+	results   map[string][]types.BatchResult
+	submitted int
+	jobs      map[string]types.BatchJob
+}
+
+func (f *fakeBatcher) SubmitBatch(_ context.Context, requests []types.BatchRequest) (types.BatchJob, error) {
+	outcome := f.outcomes[f.submitted]
+	f.submitted++
+	outcome.ID = fmt.Sprintf("batch_%d", f.submitted)
+	outcome.Total = len(requests)
+	if f.jobs == nil {
+		f.jobs = make(map[string]types.BatchJob)
+	}
+	f.jobs[outcome.ID] = outcome
+	return types.BatchJob{ID: outcome.ID, Status: types.BatchStatusValidating, Total: len(requests)}, nil
+}
+
+func (f *fakeBatcher) BatchStatus(_ context.Context, batchID string) (types.BatchJob, error) {
+	return f.jobs[batchID], nil
+}
+
+func (f *fakeBatcher) BatchResults(_ context.Context, job types.BatchJob) ([]types.BatchResult, error) {
+	return f.results[job.ID], nil
+}
+
+func (f *fakeBatcher) CancelBatch(_ context.Context, batchID string) (types.BatchJob, error) {
+	job := f.jobs[batchID]
+	job.Status = types.BatchStatusCancelled
+	f.jobs[batchID] = job
+	return job, nil
+}
+
+var requests = []types.BatchRequest{{CustomID: "a"}, {CustomID: "b"}}
+
+func newRunner(t *testing.T, batcher *fakeBatcher) *batch.Runner {
+	return batch.New(batcher, filepath.Join(t.TempDir(), "state", "batch.json"), batch.WithPollInterval(time.Millisecond))
+}
+
+func TestRunCollectsResults(t *testing.T) {
+	batcher := &fakeBatcher{
+		outcomes: []types.BatchJob{{Status: types.BatchStatusExpired, OutputFileID: "file_out", ErrorFileID: "file_err"}},
+		results: map[string][]types.BatchResult{
+			"batch_1": {{CustomID: "a", Error: "400: invalid request"}},
+		},
+	}
+	runner := newRunner(t, batcher)
+
+	results, err := runner.Run(context.Background(), requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results["a"].Error != "400: invalid request" {
+		t.Errorf("result a = %+v, want the error file entry", results["a"])
+	}
+	if !strings.Contains(results["b"].Error, "expired") {
+		t.Errorf("result b = %+v, want a missing result error", results["b"])
+	}
+
+	// Completed jobs are served from the state file
+	if _, err := runner.Run(context.Background(), requests); err != nil {
+		t.Fatal(err)
+	}
+	if batcher.submitted != 1 {
+		t.Errorf("submitted %d jobs, want 1", batcher.submitted)
+	}
+}
+
+func TestRunResubmitsFailedJob(t *testing.T) {
+	batcher := &fakeBatcher{
+		outcomes: []types.BatchJob{
+			{Status: types.BatchStatusFailed, Errors: []string{"invalid_json_line: line 2 is not valid JSON"}},
+			{Status: types.BatchStatusCompleted, OutputFileID: "file_out"},
+		},
+		results: map[string][]types.BatchResult{
+			"batch_2": {{CustomID: "a"}, {CustomID: "b"}},
+		},
+	}
+	runner := newRunner(t, batcher)
+
+	_, err := runner.Run(context.Background(), requests)
+	if !errors.Is(err, batch.ErrJobFailed) || !strings.Contains(err.Error(), "line 2 is not valid JSON") {
+		t.Fatalf("Run error = %v, want ErrJobFailed with the job errors", err)
+	}
+
+	results, err := runner.Run(context.Background(), requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batcher.submitted != 2 || len(results) != 2 {
+		t.Errorf("submitted %d jobs with %d results, want a resubmitted job", batcher.submitted, len(results))
+	}
+}
+
+func TestRunRejectsOtherRequests(t *testing.T) {
+	batcher := &fakeBatcher{
+		outcomes: []types.BatchJob{
+			{Status: types.BatchStatusCompleted, OutputFileID: "file_out"},
+			{Status: types.BatchStatusCompleted, OutputFileID: "file_out"},
+		},
+	}
+	runner := newRunner(t, batcher)
+	if _, err := runner.Run(context.Background(), requests); err != nil {
+		t.Fatal(err)
+	}
+
+	other := []types.BatchRequest{{CustomID: "c"}}
+	if _, err := runner.Run(context.Background(), other); !errors.Is(err, batch.ErrStateMismatch) {
+		t.Fatalf("Run error = %v, want ErrStateMismatch", err)
+	}
+
+	if err := runner.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Run(context.Background(), other); err != nil {
+		t.Fatalf("Run after Reset: %v", err)
+	}
+}
+
+func TestRunRejectsEditedRequests(t *testing.T) {
+	batcher := &fakeBatcher{
+		outcomes: []types.BatchJob{
+			{Status: types.BatchStatusCompleted, OutputFileID: "file_out"},
+			{Status: types.BatchStatusCompleted, OutputFileID: "file_out"},
+		},
+	}
+	runner := newRunner(t, batcher)
+	original := []types.BatchRequest{{CustomID: "a", Context: types.Context{SystemPrompt: "Be brief"}}}
+	if _, err := runner.Run(context.Background(), original); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := []types.BatchRequest{{CustomID: "a", Context: types.Context{SystemPrompt: "Be thorough"}}}
+	if _, err := runner.Run(context.Background(), edited); !errors.Is(err, batch.ErrStateMismatch) {
+		t.Fatalf("Run error = %v, want ErrStateMismatch", err)
+	}
+
+	if err := runner.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Run(context.Background(), edited); err != nil {
+		t.Fatal(err)
+	}
+	if batcher.submitted != 2 {
+		t.Errorf("submitted %d jobs, want a new job for the edited request", batcher.submitted)
+	}
+}
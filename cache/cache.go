@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Entry is a cached model response
+type Entry struct {
+	Message types.AssistantMessage
+	// Deltas holds the streamed fragments of each content block, indexed like
+	// Message.Contents. It is empty for entries populated by Complete.
+	Deltas    [][]string
+	CreatedAt time.Time
+}
+
+// Backend stores cache entries by key
+type Backend interface {
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	Set(ctx context.Context, key string, entry Entry) error
+}
+
+type bypassKey struct{}
+
+// Bypass returns a context whose calls skip the cache lookup but still
+// refresh the stored entry.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+func bypassed(ctx context.Context) bool {
+	v, _ := ctx.Value(bypassKey{}).(bool)
+	return v
+}
+
+// Middleware returns a provider middleware that serves identical requests
+// from backend. Failed and aborted responses are never cached.
+func Middleware(backend Backend) provider.Middleware {
+	return func(next provider.Provider) provider.Provider {
+		return &cachedProvider{
+			next:    next,
+			backend: backend,
+		}
+	}
+}
+
+type cachedProvider struct {
+	next    provider.Provider
+	backend Backend
+}
+
+func (p *cachedProvider) Unwrap() provider.Provider {
+	return p.next
+}
+
+func (p *cachedProvider) Model() string {
+	return p.next.Model()
+}
+
+func (p *cachedProvider) ProviderType() types.ModelProvider {
+	return p.next.ProviderType()
+}
+
+func (p *cachedProvider) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	key, keyErr := Key(p.next.ProviderType(), p.next.Model(), conversation)
+
+	if keyErr == nil && !bypassed(ctx) {
+		if entry, ok, err := p.backend.Get(ctx, key); err == nil && ok {
+			message := entry.Message
+			message.Timestamp = time.Now()
+			return message, nil
+		}
+	}
+
+	message, err := p.next.Complete(ctx, conversation)
+	if err == nil && keyErr == nil && cacheable(message) {
+		_ = p.backend.Set(ctx, key, Entry{
+			Message:   message,
+			CreatedAt: time.Now(),
+		})
+	}
+	return message, err
+}
+
+func (p *cachedProvider) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
+	key, keyErr := Key(p.next.ProviderType(), p.next.Model(), conversation)
+
+	if keyErr == nil && !bypassed(ctx) {
+		if entry, ok, err := p.backend.Get(ctx, key); err == nil && ok {
+			return Replay(entry)
+		}
+	}
+
+	var deltas [][]string
+	return provider.InterceptStream(
+		p.next.Stream(ctx, conversation),
+		func(event types.AssistantMessageEvent) {
+			switch e := event.(type) {
+			case types.EventTextDelta:
+				deltas = appendDelta(deltas, e.ContentIndex, e.Delta)
+			case types.EventToolcallDelta:
+				deltas = appendDelta(deltas, e.ContentIndex, e.Delta)
+			}
+		},
+		func(message types.AssistantMessage, err error) {
+			if err != nil || keyErr != nil || !cacheable(message) {
+				return
+			}
+			_ = p.backend.Set(context.WithoutCancel(ctx), key, Entry{
+				Message:   message,
+				Deltas:    deltas,
+				CreatedAt: time.Now(),
+			})
+		},
+	)
+}
+
+func appendDelta(deltas [][]string, index int, delta string) [][]string {
+	if index < 0 {
+		return deltas
+	}
+	for len(deltas) <= index {
+		deltas = append(deltas, nil)
+	}
+	deltas[index] = append(deltas[index], delta)
+	return deltas
+}
+
+func cacheable(message types.AssistantMessage) bool {
+	switch message.StopReason {
+	case types.StopReasonError, types.StopReasonAborted:
+		return false
+	}
+	return message.ErrorMessage == nil
+}
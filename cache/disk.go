@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Disk is a backend that stores one JSON file per entry in a directory
+type Disk struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDisk creates a disk backend rooted at dir; a ttl of zero disables expiry
+func NewDisk(dir string, ttl time.Duration) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Disk{
+		dir: dir,
+		ttl: ttl,
+	}, nil
+}
+
+func (d *Disk) Get(ctx context.Context, key string) (Entry, bool, error) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Entry{}, false, nil
+		}
+		return Entry{}, false, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to decode cache entry %s: %w", key, err)
+	}
+
+	if expired(entry, d.ttl) {
+		_ = os.Remove(d.path(key))
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (d *Disk) Set(ctx context.Context, key string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	// Write to a temp file first so concurrent readers never see partial entries
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+func (d *Disk) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDiskRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	disk, err := NewDisk(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := entry("cached", time.Now())
+	if err := disk.Set(ctx, "key", want); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, ok, err := disk.Get(ctx, "key")
+	if err != nil || !ok {
+		t.Fatalf("Get = %v, %v", ok, err)
+	}
+	if got.Message.Contents[0] != want.Message.Contents[0] {
+		t.Errorf("contents = %#v", got.Message.Contents)
+	}
+	if _, ok, _ := disk.Get(ctx, "missing"); ok {
+		t.Error("missing key was found")
+	}
+}
@@ -0,0 +1,12 @@
+package cache
+
+import (
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/requestkey"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Key returns the cache key for a request. Timestamps and other fields that
+// do not influence generation are excluded, so re-sent prompts share a key.
+func Key(providerType types.ModelProvider, modelID string, conversation types.Context) (string, error) {
+	return requestkey.Key(providerType, modelID, conversation)
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func conversation(text string) types.Context {
+	return types.Context{
+		SystemPrompt: "Be brief.",
+		Messages: []types.Message{
+			types.UserMessage{Contents: []types.Content{types.TextContent{Text: text}}, Timestamp: time.Now()},
+		},
+	}
+}
+
+func mustKey(t *testing.T, providerType types.ModelProvider, modelID string, conversation types.Context) string {
+	t.Helper()
+
+	key, err := Key(providerType, modelID, conversation)
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	return key
+}
+
+func TestKeyIgnoresTimestamps(t *testing.T) {
+	a := conversation("hello")
+	b := conversation("hello")
+	b.Messages[0] = types.UserMessage{Contents: b.Messages[0].Content(), Timestamp: time.Unix(0, 0)}
+
+	if mustKey(t, types.ProviderOpenAI, "gpt-4o", a) != mustKey(t, types.ProviderOpenAI, "gpt-4o", b) {
+		t.Error("keys differ for requests that only differ in timestamps")
+	}
+}
+
+func TestKeyDistinguishesRequests(t *testing.T) {
+	base := conversation("hello")
+	baseKey := mustKey(t, types.ProviderOpenAI, "gpt-4o", base)
+
+	withTool := conversation("hello")
+	withTool.Tools = []types.Tool{{Name: "search", Description: "Search the web"}}
+
+	withSystem := conversation("hello")
+	withSystem.SystemPrompt = "Be verbose."
+
+	withFormat := conversation("hello")
+	withFormat.ResponseFormat = &types.ResponseFormat{Type: types.ResponseFormatJSONObject}
+
+	toolResult := conversation("hello")
+	toolResult.Messages = append(toolResult.Messages, types.ToolMessage{
+		ToolCallId: "call_1",
+		ToolName:   "search",
+		Contents:   []types.Content{types.TextContent{Text: "hello"}},
+	})
+	failedToolResult := conversation("hello")
+	failedToolResult.Messages = append(failedToolResult.Messages, types.ToolMessage{
+		ToolCallId: "call_1",
+		ToolName:   "search",
+		Contents:   []types.Content{types.TextContent{Text: "hello"}},
+		IsError:    true,
+	})
+
+	tests := []struct {
+		name         string
+		providerType types.ModelProvider
+		modelID      string
+		conversation types.Context
+	}{
+		{"provider", types.ProviderNvidia, "gpt-4o", base},
+		{"model", types.ProviderOpenAI, "gpt-4o-mini", base},
+		{"message text", types.ProviderOpenAI, "gpt-4o", conversation("hello!")},
+		{"system prompt", types.ProviderOpenAI, "gpt-4o", withSystem},
+		{"tools", types.ProviderOpenAI, "gpt-4o", withTool},
+		{"response format", types.ProviderOpenAI, "gpt-4o", withFormat},
+		{"tool result", types.ProviderOpenAI, "gpt-4o", toolResult},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if mustKey(t, tt.providerType, tt.modelID, tt.conversation) == baseKey {
+				t.Error("key matches the base request")
+			}
+		})
+	}
+
+	if mustKey(t, types.ProviderOpenAI, "gpt-4o", toolResult) == mustKey(t, types.ProviderOpenAI, "gpt-4o", failedToolResult) {
+		t.Error("IsError does not change the key")
+	}
+}
+
+func TestKeyIncludesAudio(t *testing.T) {
+	textOnly := conversation("say hi")
+	wav := conversation("say hi")
+	wav.Audio = &types.AudioOutput{Voice: "alloy", Format: "wav"}
+	mp3 := conversation("say hi")
+	mp3.Audio = &types.AudioOutput{Voice: "alloy", Format: "mp3"}
+	otherVoice := conversation("say hi")
+	otherVoice.Audio = &types.AudioOutput{Voice: "verse", Format: "wav"}
+
+	keys := map[string]string{}
+	for name, c := range map[string]types.Context{"text only": textOnly, "wav": wav, "mp3": mp3, "other voice": otherVoice} {
+		key := mustKey(t, types.ProviderOpenAI, "gpt-4o-audio-preview", c)
+		if previous, ok := keys[key]; ok {
+			t.Errorf("%s and %s share a key", name, previous)
+		}
+		keys[key] = name
+	}
+}
+
+func TestKeyIncludesAudioContent(t *testing.T) {
+	audio := func(data string) types.Context {
+		return types.Context{Messages: []types.Message{
+			types.UserMessage{Contents: []types.Content{types.AudioContent{Format: "wav", Data: data}}},
+		}}
+	}
+
+	if mustKey(t, types.ProviderOpenAI, "gpt-4o-audio-preview", audio("UklGRg==")) == mustKey(t, types.ProviderOpenAI, "gpt-4o-audio-preview", audio("UklGRh==")) {
+		t.Error("different audio input shares a key")
+	}
+}
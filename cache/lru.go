@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-memory backend that evicts the least recently used entry
+// once capacity is reached. Entries older than the TTL are treated as misses.
+type LRU struct {
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+	mu       sync.Mutex
+}
+
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+// NewLRU creates an LRU backend; a ttl of zero disables expiry
+func NewLRU(capacity int, ttl time.Duration) *LRU {
+	return &LRU{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) (Entry, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	item := elem.Value.(*lruItem)
+	if expired(item.entry, c.ttl) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return Entry{}, false, nil
+	}
+
+	c.order.MoveToFront(elem)
+	return item.entry, true, nil
+}
+
+func (c *LRU) Set(ctx context.Context, key string, entry Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// Len returns the number of stored entries
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func expired(entry Entry, ttl time.Duration) bool {
+	return ttl > 0 && time.Since(entry.CreatedAt) > ttl
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func entry(text string, createdAt time.Time) Entry {
+	return Entry{
+		Message:   types.AssistantMessage{Contents: []types.Content{types.TextContent{Text: text}}},
+		CreatedAt: createdAt,
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2, 0)
+
+	lru.Set(ctx, "a", entry("a", time.Now()))
+	lru.Set(ctx, "b", entry("b", time.Now()))
+	// Touch a so b becomes the least recently used
+	if _, ok, _ := lru.Get(ctx, "a"); !ok {
+		t.Fatal("a missing")
+	}
+	lru.Set(ctx, "c", entry("c", time.Now()))
+
+	if _, ok, _ := lru.Get(ctx, "b"); ok {
+		t.Error("b was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := lru.Get(ctx, key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if lru.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", lru.Len())
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(10, time.Minute)
+
+	lru.Set(ctx, "old", entry("old", time.Now().Add(-2*time.Minute)))
+	lru.Set(ctx, "new", entry("new", time.Now()))
+
+	if _, ok, _ := lru.Get(ctx, "old"); ok {
+		t.Error("expired entry was returned")
+	}
+	if _, ok, _ := lru.Get(ctx, "new"); !ok {
+		t.Error("fresh entry missing")
+	}
+	if lru.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 after dropping the expired entry", lru.Len())
+	}
+}
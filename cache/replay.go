@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/partialjson"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Replay returns a stream that emits the same event sequence a live call
+// produced for entry.
+func Replay(entry Entry) types.AssistantMessageEventStream {
+	stream := types.NewAssistantMessageEventStream()
+
+	go func() {
+		message := entry.Message
+		message.Timestamp = time.Now()
+
+		partial := message
+		partial.Contents = []types.Content{}
+		partial.StopReason = ""
+
+		stream.Events <- types.EventStart{}
+
+		for i, content := range message.Contents {
+			deltas := blockDeltas(entry, i, content)
+
+			switch c := content.(type) {
+			case types.TextContent:
+				stream.Events <- types.EventTextStart{ContentIndex: i, Partial: partial}
+				for _, delta := range deltas {
+					stream.Events <- types.EventTextDelta{ContentIndex: i, Delta: delta, Partial: partial}
+				}
+				stream.Events <- types.EventTextEnd{ContentIndex: i, Content: c.Text, Partial: partial}
+
+			case types.ToolCall:
+				stream.Events <- types.EventToolcallStart{ContentIndex: i, ID: c.ID, Name: c.Name, Partial: partial}
+				var received strings.Builder
+				for _, delta := range deltas {
+					received.WriteString(delta)
+					stream.Events <- types.EventToolcallDelta{
+						ContentIndex: i,
+						ID:           c.ID,
+						Name:         c.Name,
+						Delta:        delta,
+						Arguments:    partialjson.ParseObject(received.String()),
+						Partial:      partial,
+					}
+				}
+				stream.Events <- types.EventToolcallEnd{ContentIndex: i, ToolCall: c, Partial: partial}
+
+			case types.AudioContent:
+				stream.Events <- types.EventAudioStart{ContentIndex: i, Partial: partial}
+				stream.Events <- types.EventAudioDelta{ContentIndex: i, Data: c.Data, Transcript: c.Transcript, Partial: partial}
+				stream.Events <- types.EventAudioEnd{ContentIndex: i, Content: c, Partial: partial}
+			}
+
+			partial.Contents = append(partial.Contents, content)
+		}
+
+		stream.Events <- types.EventDone{
+			Reason:  message.StopReason,
+			Message: message,
+		}
+		stream.Result <- message
+		stream.Err <- nil
+		stream.Close()
+	}()
+
+	return stream
+}
+
+// blockDeltas returns the recorded deltas of a block, or a single delta
+// carrying the whole block for entries cached from Complete.
+func blockDeltas(entry Entry, index int, content types.Content) []string {
+	if index < len(entry.Deltas) && len(entry.Deltas[index]) > 0 {
+		return entry.Deltas[index]
+	}
+
+	switch c := content.(type) {
+	case types.TextContent:
+		return []string{c.Text}
+	case types.ToolCall:
+		if c.RawArguments != "" {
+			return []string{c.RawArguments}
+		}
+		args, err := json.Marshal(c.Arguments)
+		if err != nil {
+			return nil
+		}
+		return []string{string(args)}
+	}
+	return nil
+}
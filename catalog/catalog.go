@@ -0,0 +1,152 @@
+// Package catalog describes the capabilities and limits of known models.
+package catalog
+
+import (
+	"fmt"
+	"mime"
+	"slices"
+	"sync"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// ModelInfo holds metadata about a model. Zero limits mean unknown/unlimited.
+type ModelInfo struct {
+	Provider        types.ModelProvider
+	ID              string
+	Name            string
+	ContextWindow   int
+	MaxOutputTokens int
+
+	SupportsTools     bool
+	SupportsImages    bool
+	SupportsDocuments bool
+	SupportsAudio     bool
+
+	MaxImageBytes    int
+	MaxDocumentBytes int
+
+	// DocumentTypes lists the MIME types accepted as native file inputs when
+	// SupportsDocuments is set; empty means DefaultDocumentTypes
+	DocumentTypes []string
+
+	// Embedding models only
+	EmbeddingDimensions int
+	MaxEmbeddingInputs  int
+}
+
+// DefaultDocumentTypes are the native file inputs accepted by document models
+var DefaultDocumentTypes = []string{"application/pdf"}
+
+// Model returns the model identifier described by info
+func (m ModelInfo) Model() types.Model {
+	return types.Model{Provider: m.Provider, ID: m.ID}
+}
+
+var (
+	mu     sync.RWMutex
+	models = map[types.Model]ModelInfo{}
+)
+
+func init() {
+	for _, info := range builtin {
+		models[info.Model()] = info
+	}
+}
+
+// Lookup returns the catalog entry for model
+func Lookup(model types.Model) (ModelInfo, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	info, ok := models[model]
+	return info, ok
+}
+
+// Register adds or replaces a catalog entry
+func Register(info ModelInfo) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	models[info.Model()] = info
+}
+
+// All returns every catalog entry
+func All() []ModelInfo {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	infos := make([]ModelInfo, 0, len(models))
+	for _, info := range models {
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Validate checks conversation attachments against the catalog limits of
+// model. Models missing from the catalog are not checked.
+func Validate(model types.Model, conversation types.Context) error {
+	info, ok := Lookup(model)
+	if !ok {
+		return nil
+	}
+
+	for _, message := range conversation.Messages {
+		var contents []types.Content
+		switch msg := message.(type) {
+		case types.UserMessage:
+			contents = msg.Contents
+		case types.ToolMessage:
+			contents = msg.Contents
+		}
+
+		for _, content := range contents {
+			if err := info.validateContent(content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m ModelInfo) validateContent(content types.Content) error {
+	switch c := content.(type) {
+	case types.ImageContent:
+		if !m.SupportsImages {
+			return fmt.Errorf("%w: %s does not accept images", types.ErrUnsupportedContent, m.ID)
+		}
+		if m.MaxImageBytes > 0 && c.Size() > m.MaxImageBytes {
+			return fmt.Errorf("%w: image is %d bytes, %s accepts at most %d", types.ErrContentTooLarge, c.Size(), m.ID, m.MaxImageBytes)
+		}
+
+	case types.AudioContent:
+		if !m.SupportsAudio {
+			return fmt.Errorf("%w: %s does not accept audio", types.ErrUnsupportedContent, m.ID)
+		}
+
+	case types.DocumentContent:
+		if !c.IsText() && !m.SupportsDocuments {
+			return fmt.Errorf("%w: %s does not accept %s documents", types.ErrUnsupportedContent, m.ID, c.MimeType)
+		}
+		if !c.IsText() && !m.acceptsDocument(c.MimeType) {
+			return fmt.Errorf("%w: %s does not accept %s files", types.ErrNotSupported, m.ID, c.MimeType)
+		}
+		if m.MaxDocumentBytes > 0 && c.Size() > m.MaxDocumentBytes {
+			return fmt.Errorf("%w: document %s is %d bytes, %s accepts at most %d", types.ErrContentTooLarge, c.Filename, c.Size(), m.ID, m.MaxDocumentBytes)
+		}
+	}
+	return nil
+}
+
+// acceptsDocument reports whether mimeType is sent as a native file input
+func (m ModelInfo) acceptsDocument(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	documentTypes := m.DocumentTypes
+	if len(documentTypes) == 0 {
+		documentTypes = DefaultDocumentTypes
+	}
+	return slices.Contains(documentTypes, mediaType)
+}
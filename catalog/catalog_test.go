@@ -0,0 +1,59 @@
+package catalog_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/catalog"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func withDocument(document types.DocumentContent) types.Context {
+	return types.Context{Messages: []types.Message{
+		types.UserMessage{Contents: []types.Content{document}},
+	}}
+}
+
+func TestValidateDocuments(t *testing.T) {
+	gpt4o := types.Model{Provider: types.ProviderOpenAI, ID: "gpt-4o"}
+	textOnly := types.Model{Provider: types.ProviderOpenAI, ID: "gpt-4o-audio-preview"}
+	spreadsheets := types.Model{Provider: types.ProviderCustom, ID: "spreadsheets"}
+	catalog.Register(catalog.ModelInfo{
+		Provider:          spreadsheets.Provider,
+		ID:                spreadsheets.ID,
+		SupportsDocuments: true,
+		DocumentTypes:     []string{"text/csv", "application/vnd.ms-excel"},
+	})
+
+	pdf := types.DocumentFromBytes([]byte("%PDF-1.7"), "report.pdf")
+	docx := types.DocumentContent{Data: "UEsDBA==", MimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Filename: "report.docx"}
+	xls := types.DocumentContent{Data: "0M8R4A==", MimeType: "application/vnd.ms-excel", Filename: "sheet.xls"}
+	notes := types.DocumentFromText("notes", "notes.txt")
+	large := types.DocumentContent{Data: strings.Repeat("A", 44*1024*1024), MimeType: "application/pdf", Filename: "large.pdf"}
+
+	tests := []struct {
+		name     string
+		model    types.Model
+		document types.DocumentContent
+		want     error
+	}{
+		{"pdf", gpt4o, pdf, nil},
+		{"pdf with parameters", gpt4o, types.DocumentContent{Data: pdf.Data, MimeType: "application/pdf; name=report.pdf"}, nil},
+		{"text", textOnly, notes, nil},
+		{"unlisted type", gpt4o, docx, types.ErrNotSupported},
+		{"no document support", textOnly, pdf, types.ErrUnsupportedContent},
+		{"too large", gpt4o, large, types.ErrContentTooLarge},
+		{"listed type", spreadsheets, xls, nil},
+		{"pdf not listed", spreadsheets, pdf, types.ErrNotSupported},
+	}
+	for _, tt := range tests {
+		err := catalog.Validate(tt.model, withDocument(tt.document))
+		if tt.want == nil && err != nil {
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		}
+		if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package catalog
+
+import "github.com/rahulSailesh-shah/go-pi-ai/types"
+
+const (
+	mb = 1 << 20
+)
+
+// builtin lists the models known out of the box
+var builtin = []ModelInfo{
+	{
+		Provider:        types.ProviderNvidia,
+		ID:              "openai/gpt-oss-20b",
+		Name:            "gpt-oss-20b",
+		ContextWindow:   131072,
+		MaxOutputTokens: 32768,
+		SupportsTools:   true,
+	},
+	{
+		Provider:        types.ProviderNvidia,
+		ID:              "openai/gpt-oss-120b",
+		Name:            "gpt-oss-120b",
+		ContextWindow:   131072,
+		MaxOutputTokens: 32768,
+		SupportsTools:   true,
+	},
+	{
+		Provider:          types.ProviderOpenAI,
+		ID:                "gpt-4o",
+		Name:              "GPT-4o",
+		ContextWindow:     128000,
+		MaxOutputTokens:   16384,
+		SupportsTools:     true,
+		SupportsImages:    true,
+		SupportsDocuments: true,
+		MaxImageBytes:     20 * mb,
+		MaxDocumentBytes:  32 * mb,
+	},
+	{
+		Provider:          types.ProviderOpenAI,
+		ID:                "gpt-4o-mini",
+		Name:              "GPT-4o mini",
+		ContextWindow:     128000,
+		MaxOutputTokens:   16384,
+		SupportsTools:     true,
+		SupportsImages:    true,
+		SupportsDocuments: true,
+		MaxImageBytes:     20 * mb,
+		MaxDocumentBytes:  32 * mb,
+	},
+	{
+		Provider:          types.ProviderOpenAI,
+		ID:                "gpt-4.1",
+		Name:              "GPT-4.1",
+		ContextWindow:     1047576,
+		MaxOutputTokens:   32768,
+		SupportsTools:     true,
+		SupportsImages:    true,
+		SupportsDocuments: true,
+		MaxImageBytes:     20 * mb,
+		MaxDocumentBytes:  32 * mb,
+	},
+	{
+		Provider:          types.ProviderOpenAI,
+		ID:                "gpt-4.1-mini",
+		Name:              "GPT-4.1 mini",
+		ContextWindow:     1047576,
+		MaxOutputTokens:   32768,
+		SupportsTools:     true,
+		SupportsImages:    true,
+		SupportsDocuments: true,
+		MaxImageBytes:     20 * mb,
+		MaxDocumentBytes:  32 * mb,
+	},
+	{
+		Provider:        types.ProviderOpenAI,
+		ID:              "gpt-4o-audio-preview",
+		Name:            "GPT-4o Audio",
+		ContextWindow:   128000,
+		MaxOutputTokens: 16384,
+		SupportsTools:   true,
+		SupportsAudio:   true,
+	},
+	{
+		Provider:            types.ProviderOpenAI,
+		ID:                  "text-embedding-3-small",
+		Name:                "text-embedding-3-small",
+		ContextWindow:       8191,
+		EmbeddingDimensions: 1536,
+		MaxEmbeddingInputs:  2048,
+	},
+	{
+		Provider:            types.ProviderOpenAI,
+		ID:                  "text-embedding-3-large",
+		Name:                "text-embedding-3-large",
+		ContextWindow:       8191,
+		EmbeddingDimensions: 3072,
+		MaxEmbeddingInputs:  2048,
+	},
+	{
+		Provider:            types.ProviderNvidia,
+		ID:                  "nvidia/nv-embedqa-e5-v5",
+		Name:                "NV-EmbedQA-E5-v5",
+		ContextWindow:       512,
+		EmbeddingDimensions: 1024,
+		MaxEmbeddingInputs:  50,
+	},
+}
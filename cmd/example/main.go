@@ -62,7 +62,7 @@ func main() {
 			case types.EventTextDelta:
 				log.Printf("Text: %s", e.Delta)
 			case types.EventToolcallStart:
-				log.Println("Tool call started")
+				log.Printf("Tool call started: %s", e.Name)
 			case types.EventDone:
 				log.Println("Stream completed")
 			}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rahulSailesh-shah/go-pi-ai/types"
@@ -11,44 +14,148 @@ import (
 type ProviderConfig struct {
 	BaseURL string
 	APIKey  string
-	Models  []string
+	// Credentials, when set, supplies the API key per request instead of APIKey
+	Credentials CredentialSource
+	Models      []string
+	// EmbeddingModels are served through the provider's /embeddings endpoint
+	EmbeddingModels []string
+
+	// Headers are sent with every request, e.g. OpenAI-Organization or gateway auth
+	Headers map[string]string
+	// Timeout bounds each request attempt; zero uses the SDK default
+	Timeout time.Duration
+	// HTTPClient overrides the client for this provider, taking precedence
+	// over ProxyURL and TLS
+	HTTPClient *http.Client
+	// ProxyURL routes requests through an HTTP(S) proxy; empty honours HTTPS_PROXY
+	ProxyURL string
+	TLS      TLSConfig
+
+	// APIVersion is the api-version of an Azure OpenAI resource; BaseURL is
+	// its endpoint and Models name deployments
+	APIVersion string
+}
+
+// TLSConfig configures private CA bundles and mutual TLS
+type TLSConfig struct {
+	// CAFile is a PEM bundle trusted in addition to the system roots
+	CAFile string
+	// CertFile and KeyFile hold a PEM client certificate for mutual TLS
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables server certificate checks; for testing only
+	InsecureSkipVerify bool
 }
 
 type Config struct {
 	Providers map[types.ModelProvider]ProviderConfig
+	// Aliases maps a name such as "fast" to a "provider:modelID" reference
+	Aliases map[string]string
+	// Routes pick a model for an alias based on request properties; the
+	// first matching rule wins and the alias target is the fallback
+	Routes []RouteRule
+}
+
+// RouteRule selects Model for requests to Alias that satisfy every set condition
+type RouteRule struct {
+	Alias string
+	// Model is a "provider:modelID" reference
+	Model string
+
+	HasImages bool
+	HasTools  bool
+	// MinTokens matches requests whose estimated prompt size is at least this many tokens
+	MinTokens int
 }
 
 func NewConfig() *Config {
 	return &Config{
 		Providers: make(map[types.ModelProvider]ProviderConfig),
+		Aliases:   make(map[string]string),
+	}
+}
+
+// ParseModelRef parses a "provider:modelID" reference. Model IDs may contain
+// further colons and slashes; only the first colon separates the provider.
+func ParseModelRef(ref string) (types.Model, error) {
+	providerName, modelID, ok := strings.Cut(strings.TrimSpace(ref), ":")
+	if !ok || providerName == "" || modelID == "" {
+		return types.Model{}, fmt.Errorf("%w: model reference %q must be provider:modelID", types.ErrConfigInvalid, ref)
 	}
+	return types.Model{Provider: types.ModelProvider(providerName), ID: modelID}, nil
 }
 
 // FromEnv loads configuration from .env file, then falls back to environment variables
 func FromEnv() (*Config, error) {
-	cfg := NewConfig()
-
 	if err := godotenv.Load(); err != nil {
 		if !os.IsNotExist(err) {
 			return nil, fmt.Errorf("failed to load .env file: %w", err)
 		}
 	}
 
+	return fromEnv(os.Getenv)
+}
+
+// FromEnvFile loads configuration from the dotenv file at path, falling back
+// to environment variables it does not set. Unlike FromEnv it leaves the
+// process environment untouched, so it can be called again after the file changes.
+func FromEnvFile(path string) (*Config, error) {
+	vars, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	return fromEnv(func(key string) string {
+		if value, ok := vars[key]; ok {
+			return value
+		}
+		return os.Getenv(key)
+	})
+}
+
+// env looks up configuration variables by name
+type env func(key string) string
+
+func fromEnv(lookup env) (*Config, error) {
+	cfg := NewConfig()
+
 	// NVIDIA configuration
-	if nvidiaAPIKey := getEnv("NVIDIA_API_KEY"); nvidiaAPIKey != "" {
+	if nvidiaAPIKey, nvidiaCredentials := lookup.credentials("NVIDIA"); nvidiaAPIKey != "" || nvidiaCredentials != nil {
 		cfg.Providers[types.ProviderNvidia] = ProviderConfig{
-			BaseURL: "https://integrate.api.nvidia.com/v1",
-			APIKey:  nvidiaAPIKey,
-			Models:  []string{"openai/gpt-oss-20b"},
+			BaseURL:     "https://integrate.api.nvidia.com/v1",
+			APIKey:      nvidiaAPIKey,
+			Credentials: nvidiaCredentials,
+			Models:      lookup.listOr("NVIDIA_MODELS", "openai/gpt-oss-20b"),
+
+			EmbeddingModels: lookup.list("NVIDIA_EMBEDDING_MODELS"),
 		}
 	}
 
 	// OpenAI configuration
-	if openaiAPIKey := getEnv("OPENAI_API_KEY"); openaiAPIKey != "" {
+	if openaiAPIKey, openaiCredentials := lookup.credentials("OPENAI"); openaiAPIKey != "" || openaiCredentials != nil {
 		cfg.Providers[types.ProviderOpenAI] = ProviderConfig{
-			BaseURL: "https://api.openai.com/v1",
-			APIKey:  openaiAPIKey,
-			Models:  []string{"openai/gpt-oss-20b"},
+			BaseURL:     "https://api.openai.com/v1",
+			APIKey:      openaiAPIKey,
+			Credentials: openaiCredentials,
+			Models:      lookup.listOr("OPENAI_MODELS", "openai/gpt-oss-20b"),
+
+			EmbeddingModels: lookup.list("OPENAI_EMBEDDING_MODELS"),
+
+			Headers: lookup.openaiHeaders(),
+		}
+	}
+
+	// Azure OpenAI configuration; models are deployment names
+	azureAPIKey, azureCredentials := lookup.credentials("AZURE_OPENAI")
+	if endpoint := lookup("AZURE_OPENAI_ENDPOINT"); endpoint != "" && (azureAPIKey != "" || azureCredentials != nil) {
+		cfg.Providers[types.ProviderAzure] = ProviderConfig{
+			BaseURL:     endpoint,
+			APIKey:      azureAPIKey,
+			Credentials: azureCredentials,
+			Models:      lookup.list("AZURE_OPENAI_DEPLOYMENTS"),
+			APIVersion:  lookup("AZURE_OPENAI_API_VERSION"),
+
+			EmbeddingModels: lookup.list("AZURE_OPENAI_EMBEDDING_DEPLOYMENTS"),
 		}
 	}
 
@@ -56,6 +163,19 @@ func FromEnv() (*Config, error) {
 		return nil, fmt.Errorf("no provider configurations found")
 	}
 
+	// Aliases, e.g. MODEL_ALIASES=fast=nvidia:openai/gpt-oss-20b,smart=openai:gpt-4o
+	for _, entry := range lookup.list("MODEL_ALIASES") {
+		name, ref, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: MODEL_ALIASES entry %q must be name=provider:modelID", types.ErrConfigInvalid, entry)
+		}
+		cfg.Aliases[strings.TrimSpace(name)] = strings.TrimSpace(ref)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -67,10 +187,89 @@ func (c *Config) GetProvider(name types.ModelProvider) (ProviderConfig, error) {
 	return provider, nil
 }
 
+// Validate checks that every provider has credentials, Azure providers have
+// an endpoint, and aliases and routes reference well-formed models of
+// configured providers
+func (c *Config) Validate() error {
+	for name, provider := range c.Providers {
+		if provider.APIKey == "" && provider.Credentials == nil {
+			return fmt.Errorf("%w: provider %s has no API key or credential source", types.ErrConfigInvalid, name)
+		}
+		if name == types.ProviderAzure && provider.BaseURL == "" {
+			return fmt.Errorf("%w: provider %s has no endpoint (BaseURL)", types.ErrConfigInvalid, name)
+		}
+	}
+	for name, ref := range c.Aliases {
+		if name == "" {
+			return fmt.Errorf("%w: empty alias name", types.ErrConfigInvalid)
+		}
+		if err := c.validateModelRef(ref); err != nil {
+			return fmt.Errorf("alias %s: %w", name, err)
+		}
+	}
+	for i, route := range c.Routes {
+		if _, ok := c.Aliases[route.Alias]; !ok {
+			return fmt.Errorf("%w: route %d references unknown alias %q", types.ErrConfigInvalid, i, route.Alias)
+		}
+		if err := c.validateModelRef(route.Model); err != nil {
+			return fmt.Errorf("route %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateModelRef checks that ref is well-formed and names a configured provider
+func (c *Config) validateModelRef(ref string) error {
+	model, err := ParseModelRef(ref)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.Providers[model.Provider]; !ok {
+		return fmt.Errorf("%w: %q references unconfigured provider %s", types.ErrConfigInvalid, ref, model.Provider)
+	}
+	return nil
+}
+
 func (c *Config) SetProvider(name types.ModelProvider, provider ProviderConfig) {
 	c.Providers[name] = provider
 }
 
-func getEnv(key string) string {
-	return os.Getenv(key)
+// list reads a comma-separated list, skipping empty entries
+func (e env) list(key string) []string {
+	var values []string
+	for _, value := range strings.Split(e(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// listOr reads a comma-separated list, returning defaults when it is unset
+func (e env) listOr(key string, defaults ...string) []string {
+	if values := e.list(key); len(values) > 0 {
+		return values
+	}
+	return defaults
+}
+
+// credentials returns <prefix>_API_KEY, or a source reading the file named
+// by <prefix>_API_KEY_FILE so mounted secrets can be rotated in place
+func (e env) credentials(prefix string) (string, CredentialSource) {
+	if path := e(prefix + "_API_KEY_FILE"); path != "" {
+		return "", FileCredential(path)
+	}
+	return e(prefix + "_API_KEY"), nil
+}
+
+// openaiHeaders scopes OpenAI requests to an organization and project when configured
+func (e env) openaiHeaders() map[string]string {
+	headers := map[string]string{}
+	if org := e("OPENAI_ORG_ID"); org != "" {
+		headers["OpenAI-Organization"] = org
+	}
+	if project := e("OPENAI_PROJECT_ID"); project != "" {
+		headers["OpenAI-Project"] = project
+	}
+	return headers
 }
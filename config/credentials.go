@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// refreshMargin is how long before expiry a token is refreshed; short-lived
+// tokens are refreshed after half their lifetime
+const refreshMargin = time.Minute
+
+// CredentialSource supplies the API key of a provider. It is consulted on
+// every request, so rotated keys take effect without recreating clients.
+// Sources may implement Equal(CredentialSource) bool so that reloading an
+// unchanged config keeps the provider.
+type CredentialSource interface {
+	Credential(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts a function to a CredentialSource
+type CredentialFunc func(ctx context.Context) (string, error)
+
+func (f CredentialFunc) Credential(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticCredential always returns key
+func StaticCredential(key string) CredentialSource {
+	return staticCredential(key)
+}
+
+type staticCredential string
+
+func (s staticCredential) Credential(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// EnvCredential reads the environment variable name on every request
+func EnvCredential(name string) CredentialSource {
+	return envCredential(name)
+}
+
+type envCredential string
+
+func (e envCredential) Credential(context.Context) (string, error) {
+	if key := os.Getenv(string(e)); key != "" {
+		return key, nil
+	}
+	return "", fmt.Errorf("%w: environment variable %s is not set", types.ErrCredentials, string(e))
+}
+
+// FileCredential reads the key from path, e.g. a mounted secret, and reloads
+// it whenever the file changes
+func FileCredential(path string) CredentialSource {
+	return &fileCredential{path: path}
+}
+
+type fileCredential struct {
+	path    string
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+// Equal reports whether other reads the same file
+func (f *fileCredential) Equal(other CredentialSource) bool {
+	o, ok := other.(*fileCredential)
+	return ok && o.path == f.path
+}
+
+func (f *fileCredential) Credential(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", types.ErrCredentials, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", types.ErrCredentials, err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is empty", types.ErrCredentials, f.path)
+	}
+
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+	return f.key, nil
+}
+
+// Token is a credential that may expire
+type Token struct {
+	Value string
+	// ExpiresAt is zero for tokens that never expire
+	ExpiresAt time.Time
+}
+
+// RefreshingCredential caches the token returned by fetch and fetches a new
+// one shortly before it expires. If a refresh fails while the cached token is
+// still valid, the cached token is used.
+func RefreshingCredential(fetch func(ctx context.Context) (Token, error)) CredentialSource {
+	return &refreshingCredential{fetch: fetch}
+}
+
+type refreshingCredential struct {
+	fetch     func(ctx context.Context) (Token, error)
+	mu        sync.Mutex
+	token     Token
+	refreshAt time.Time
+}
+
+func (r *refreshingCredential) Credential(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.token.Value != "" && (r.token.ExpiresAt.IsZero() || now.Before(r.refreshAt)) {
+		return r.token.Value, nil
+	}
+
+	token, err := r.fetch(ctx)
+	if err == nil && token.Value == "" {
+		err = fmt.Errorf("empty token")
+	}
+	if err != nil {
+		if r.token.Value != "" && now.Before(r.token.ExpiresAt) {
+			return r.token.Value, nil
+		}
+		return "", fmt.Errorf("%w: %v", types.ErrCredentials, err)
+	}
+
+	r.token = token
+	r.refreshAt = token.ExpiresAt.Add(-min(refreshMargin, token.ExpiresAt.Sub(now)/2))
+	return r.token.Value, nil
+}
+
+// CommandCredential runs a credential helper and uses its trimmed standard
+// output as the key for ttl; a zero ttl runs the helper on every request
+func CommandCredential(ttl time.Duration, name string, args ...string) CredentialSource {
+	return RefreshingCredential(func(ctx context.Context) (Token, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return Token{}, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+		return Token{
+			Value:     strings.TrimSpace(stdout.String()),
+			ExpiresAt: time.Now().Add(ttl),
+		}, nil
+	})
+}
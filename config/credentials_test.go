@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func credential(t *testing.T, source CredentialSource) string {
+	t.Helper()
+	key, err := source.Credential(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestEnvCredential(t *testing.T) {
+	source := EnvCredential("PI_AI_TEST_KEY")
+
+	t.Setenv("PI_AI_TEST_KEY", "")
+	if _, err := source.Credential(context.Background()); !errors.Is(err, types.ErrCredentials) {
+		t.Errorf("unset variable error = %v, want ErrCredentials", err)
+	}
+
+	t.Setenv("PI_AI_TEST_KEY", "sk-1")
+	if key := credential(t, source); key != "sk-1" {
+		t.Errorf("key = %q", key)
+	}
+	t.Setenv("PI_AI_TEST_KEY", "sk-2")
+	if key := credential(t, source); key != "sk-2" {
+		t.Errorf("rotated key = %q", key)
+	}
+}
+
+func TestFileCredential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	source := FileCredential(path)
+
+	if _, err := source.Credential(context.Background()); !errors.Is(err, types.ErrCredentials) {
+		t.Errorf("missing file error = %v, want ErrCredentials", err)
+	}
+
+	if err := os.WriteFile(path, []byte("sk-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if key := credential(t, source); key != "sk-1" {
+		t.Errorf("key = %q", key)
+	}
+
+	// A rotated secret is picked up once the file changes
+	if err := os.WriteFile(path, []byte("sk-rotated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if key := credential(t, source); key != "sk-rotated" {
+		t.Errorf("rotated key = %q", key)
+	}
+
+	if err := os.WriteFile(path, []byte("  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Credential(context.Background()); !errors.Is(err, types.ErrCredentials) {
+		t.Errorf("empty file error = %v, want ErrCredentials", err)
+	}
+}
+
+func TestRefreshingCredential(t *testing.T) {
+	var (
+		fetches int
+		next    Token
+		failure error
+	)
+	source := RefreshingCredential(func(context.Context) (Token, error) {
+		fetches++
+		return next, failure
+	})
+
+	next = Token{Value: "token-1", ExpiresAt: time.Now().Add(time.Hour)}
+	credential(t, source)
+	if key := credential(t, source); key != "token-1" || fetches != 1 {
+		t.Errorf("key = %q after %d fetches, want a cached token-1", key, fetches)
+	}
+
+	// Within the refresh margin a new token is fetched
+	source.(*refreshingCredential).refreshAt = time.Now().Add(-time.Second)
+	next = Token{Value: "token-2", ExpiresAt: time.Now().Add(time.Hour)}
+	if key := credential(t, source); key != "token-2" || fetches != 2 {
+		t.Errorf("key = %q after %d fetches, want refreshed token-2", key, fetches)
+	}
+
+	// A failed refresh keeps the token while it is still valid
+	source.(*refreshingCredential).refreshAt = time.Now().Add(-time.Second)
+	failure = errors.New("token endpoint unavailable")
+	if key := credential(t, source); key != "token-2" {
+		t.Errorf("key = %q, want the cached token after a failed refresh", key)
+	}
+
+	source.(*refreshingCredential).token.ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := source.Credential(context.Background()); !errors.Is(err, types.ErrCredentials) {
+		t.Errorf("expired token error = %v, want ErrCredentials", err)
+	}
+}
+
+func TestRefreshingCredentialRejectsEmptyToken(t *testing.T) {
+	source := RefreshingCredential(func(context.Context) (Token, error) {
+		return Token{}, nil
+	})
+	if _, err := source.Credential(context.Background()); !errors.Is(err, types.ErrCredentials) {
+		t.Errorf("error = %v, want ErrCredentials", err)
+	}
+}
+
+func TestCommandCredential(t *testing.T) {
+	if key := credential(t, CommandCredential(time.Minute, "echo", " sk-helper ")); key != "sk-helper" {
+		t.Errorf("key = %q", key)
+	}
+
+	_, err := CommandCredential(time.Minute, "sh", "-c", "echo denied >&2; exit 1").Credential(context.Background())
+	if !errors.Is(err, types.ErrCredentials) || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("failing helper error = %v, want ErrCredentials with its stderr", err)
+	}
+}
@@ -2,10 +2,12 @@ module github.com/rahulSailesh-shah/go-pi-ai
 
 go 1.22
 
-require github.com/openai/openai-go/v3 v3.17.0
+require (
+	github.com/joho/godotenv v1.5.1
+	github.com/openai/openai-go/v3 v3.17.0
+)
 
 require (
-	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
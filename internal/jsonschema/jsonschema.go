@@ -0,0 +1,332 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	byteSliceType = reflect.TypeOf([]byte{})
+)
+
+// ErrNotStrict is returned by Generate in strict mode for types strict
+// structured outputs cannot describe: maps, recursive types, values of any
+// type and roots that are not structs
+var ErrNotStrict = errors.New("type cannot be described by a strict schema")
+
+// Generate derives a JSON schema from t. Field names follow encoding/json
+// tags and a `description:"..."` tag adds a description, and pointers are
+// nullable. In strict mode every property is required and objects forbid
+// additional properties, as required by OpenAI strict structured outputs.
+// Otherwise fields tagged omitempty are optional.
+func Generate(t reflect.Type, strict bool) (map[string]any, error) {
+	g := &generator{
+		strict:   strict,
+		visiting: make(map[reflect.Type]bool),
+	}
+	if strict {
+		root := t
+		for root.Kind() == reflect.Pointer {
+			root = root.Elem()
+		}
+		if root.Kind() != reflect.Struct || root == timeType {
+			return nil, fmt.Errorf("%w: %s is not a struct", ErrNotStrict, t)
+		}
+	}
+	schema := g.schema(t, "$")
+	if g.loose != "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotStrict, g.loose)
+	}
+	return schema, nil
+}
+
+type generator struct {
+	strict   bool
+	visiting map[reflect.Type]bool
+	// loose describes the first schema strict mode cannot express
+	loose string
+}
+
+// accept returns a schema accepting any value, which strict mode rejects
+func (g *generator) accept(path, reason string) map[string]any {
+	if g.strict && g.loose == "" {
+		g.loose = path + ": " + reason
+	}
+	return map[string]any{}
+}
+
+func (g *generator) schema(t reflect.Type, path string) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawJSONType:
+		return g.accept(path, "json.RawMessage holds any value")
+	case byteSliceType:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		inner := g.schema(t.Elem(), path)
+		if typ, ok := inner["type"].(string); ok {
+			inner["type"] = []any{typ, "null"}
+		}
+		return inner
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem(), path+"[]")}
+	case reflect.Map:
+		if g.strict {
+			return g.accept(path, "maps have no fixed properties")
+		}
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem(), path+"[]")}
+	case reflect.Struct:
+		return g.object(t, path)
+	default:
+		return g.accept(path, t.String()+" holds any value")
+	}
+}
+
+func (g *generator) object(t reflect.Type, path string) map[string]any {
+	// Recursive types cannot be inlined; accept any value at the cycle
+	if g.visiting[t] {
+		return g.accept(path, t.String()+" is recursive")
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	properties := make(map[string]any)
+	required := []string{}
+	g.fields(t, path, properties, &required)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if g.strict {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+func (g *generator) fields(t reflect.Type, path string, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, omitempty, skip := parseTag(field)
+		if skip {
+			continue
+		}
+
+		// Embedded structs without a name are flattened like encoding/json does
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, path, properties, required)
+				continue
+			}
+		}
+
+		schema := g.schema(field.Type, path+"."+name)
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+		properties[name] = schema
+
+		if g.strict || !omitempty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func parseTag(field reflect.StructField) (name string, omitempty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+// Validate checks a decoded JSON value against the subset of JSON schema
+// produced by Generate: type, properties, required, additionalProperties,
+// items and enum.
+func Validate(schema map[string]any, value any) error {
+	return validate(schema, value, "$")
+}
+
+func validate(schema map[string]any, value any, path string) error {
+	if typ, ok := schema["type"]; ok {
+		if !matchesType(typ, value) {
+			return fmt.Errorf("%s: expected %v, got %s", path, typ, jsonType(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(schema, v, path)
+	case []any:
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range v {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateObject(schema map[string]any, value map[string]any, path string) error {
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := value[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, name)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]any); ok {
+			if err := validate(propSchema, value[key], propPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property", propPath)
+			}
+		case map[string]any:
+			if err := validate(additional, value[key], propPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func stringList(v any) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func matchesType(typ any, value any) bool {
+	switch t := typ.(type) {
+	case string:
+		return matchesSingleType(t, value)
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && matchesSingleType(s, value) {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, s := range t {
+			if matchesSingleType(s, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(typ string, value any) bool {
+	switch typ {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == typ
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,166 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city" description:"City name"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type person struct {
+	Name     string    `json:"name"`
+	Age      int       `json:"age"`
+	Email    *string   `json:"email"`
+	Tags     []string  `json:"tags,omitempty"`
+	Born     time.Time `json:"born"`
+	Internal string    `json:"-"`
+	address
+}
+
+type withMap struct {
+	Labels map[string]string `json:"labels"`
+}
+
+type node struct {
+	Value    int    `json:"value"`
+	Children []node `json:"children"`
+}
+
+type withAny struct {
+	Extra any `json:"extra"`
+}
+
+func generate(t *testing.T, v any, strict bool) map[string]any {
+	t.Helper()
+	schema, err := Generate(reflect.TypeOf(v), strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Round trip so comparisons see the shape sent to providers
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestGenerateStrict(t *testing.T) {
+	var want map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["name", "age", "email", "tags", "born", "city", "zip"],
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer"},
+			"email": {"type": ["string", "null"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"born": {"type": "string", "format": "date-time"},
+			"city": {"type": "string", "description": "City name"},
+			"zip": {"type": "string"}
+		}
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := generate(t, person{}, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateOptionalFields(t *testing.T) {
+	schema := generate(t, person{}, false)
+	required := stringList(schema["required"])
+	if want := []string{"name", "age", "email", "born", "city"}; !reflect.DeepEqual(required, want) {
+		t.Errorf("required = %v, want %v", required, want)
+	}
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Error("non-strict schema forbids additional properties")
+	}
+}
+
+func TestGenerateRejectsLooseTypesInStrictMode(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		path string
+	}{
+		{"map", withMap{}, "$.labels"},
+		{"recursive", node{}, "$.children[]"},
+		{"any", withAny{}, "$.extra"},
+		{"non-struct root", []person{}, "not a struct"},
+	}
+	for _, tt := range tests {
+		_, err := Generate(reflect.TypeOf(tt.v), true)
+		if !errors.Is(err, ErrNotStrict) || !strings.Contains(err.Error(), tt.path) {
+			t.Errorf("%s: Generate error = %v, want ErrNotStrict at %s", tt.name, err, tt.path)
+		}
+		if _, err := Generate(reflect.TypeOf(tt.v), false); err != nil {
+			t.Errorf("%s: non-strict Generate error = %v", tt.name, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := generate(t, person{}, true)
+	valid := `{"name": "Ada", "age": 36, "email": null, "tags": [], "born": "1815-12-10T00:00:00Z", "city": "London", "zip": ""}`
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"valid", valid, ""},
+		{"missing property", `{"name": "Ada"}`, `missing required property "age"`},
+		{"wrong type", strings.Replace(valid, `"age": 36`, `"age": "36"`, 1), "$.age: expected integer"},
+		{"non-integer", strings.Replace(valid, `"age": 36`, `"age": 36.5`, 1), "$.age: expected integer"},
+		{"wrong item type", strings.Replace(valid, `"tags": []`, `"tags": [1]`, 1), "$.tags[0]"},
+		{"additional property", strings.Replace(valid, `{`, `{"extra": 1, `, 1), "$.extra: unexpected property"},
+	}
+	for _, tt := range tests {
+		var value any
+		if err := json.Unmarshal([]byte(tt.json), &value); err != nil {
+			t.Fatal(err)
+		}
+		err := Validate(schema, value)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: Validate = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateMapValues(t *testing.T) {
+	schema := generate(t, withMap{}, false)
+	if err := Validate(schema, map[string]any{"labels": map[string]any{"env": "prod"}}); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+	if err := Validate(schema, map[string]any{"labels": map[string]any{"replicas": 3.0}}); err == nil {
+		t.Error("Validate accepted a number as a map value of strings")
+	}
+}
+
+func TestValidateNullPointerWithoutStrict(t *testing.T) {
+	schema := generate(t, person{}, false)
+	if typ := schema["properties"].(map[string]any)["email"].(map[string]any)["type"]; !reflect.DeepEqual(typ, []any{"string", "null"}) {
+		t.Errorf("email type = %v, want nullable string", typ)
+	}
+
+	value := map[string]any{"name": "Ada", "age": 36.0, "email": nil, "born": "1815-12-10T00:00:00Z", "city": "London"}
+	if err := Validate(schema, value); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package partialjson
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Parse decodes as much of a possibly truncated JSON document as possible.
+// Unterminated strings and numbers are kept, incomplete keys and literals are
+// dropped, and open objects and arrays are closed. The boolean reports
+// whether any value could be recovered.
+func Parse(s string) (any, bool) {
+	p := parser{s: s}
+	v, present, _ := p.value()
+	return v, present
+}
+
+// ParseObject is like Parse but always returns a map, empty when s does not
+// start with a JSON object.
+func ParseObject(s string) map[string]any {
+	if v, ok := Parse(s); ok {
+		if obj, ok := v.(map[string]any); ok {
+			return obj
+		}
+	}
+	return map[string]any{}
+}
+
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		switch p.s[p.i] {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+// value parses the next value, reporting whether a usable value was found
+// and whether it was complete.
+func (p *parser) value() (v any, present bool, complete bool) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, false, false
+	}
+
+	switch c := p.s[p.i]; {
+	case c == '{':
+		obj, complete := p.object()
+		return obj, true, complete
+	case c == '[':
+		arr, complete := p.array()
+		return arr, true, complete
+	case c == '"':
+		str, complete := p.string()
+		return str, true, complete
+	case c == 't':
+		return p.literal("true", true)
+	case c == 'f':
+		return p.literal("false", false)
+	case c == 'n':
+		return p.literal("null", nil)
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.number()
+	default:
+		return nil, false, false
+	}
+}
+
+func (p *parser) object() (map[string]any, bool) {
+	obj := map[string]any{}
+	p.i++ // {
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return obj, false
+		}
+
+		switch p.s[p.i] {
+		case '}':
+			p.i++
+			return obj, true
+		case ',':
+			p.i++
+			continue
+		case '"':
+		default:
+			return obj, false
+		}
+
+		key, complete := p.string()
+		if !complete {
+			return obj, false
+		}
+
+		p.skipSpace()
+		if p.eof() || p.s[p.i] != ':' {
+			return obj, false
+		}
+		p.i++
+
+		v, present, complete := p.value()
+		if present {
+			obj[key] = v
+		}
+		if !complete {
+			return obj, false
+		}
+	}
+}
+
+func (p *parser) array() ([]any, bool) {
+	arr := []any{}
+	p.i++ // [
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return arr, false
+		}
+
+		switch p.s[p.i] {
+		case ']':
+			p.i++
+			return arr, true
+		case ',':
+			p.i++
+			continue
+		}
+
+		v, present, complete := p.value()
+		if present {
+			arr = append(arr, v)
+		}
+		if !complete {
+			return arr, false
+		}
+	}
+}
+
+func (p *parser) string() (string, bool) {
+	var sb strings.Builder
+	p.i++ // opening quote
+
+	for !p.eof() {
+		c := p.s[p.i]
+		switch {
+		case c == '"':
+			p.i++
+			return sb.String(), true
+
+		case c == '\\':
+			if p.i+1 >= len(p.s) {
+				p.i = len(p.s)
+				return sb.String(), false
+			}
+			esc := p.s[p.i+1]
+			p.i += 2
+			switch esc {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'u':
+				if p.i+4 > len(p.s) {
+					p.i = len(p.s)
+					return sb.String(), false
+				}
+				code, err := strconv.ParseUint(p.s[p.i:p.i+4], 16, 32)
+				if err != nil {
+					return sb.String(), false
+				}
+				p.i += 4
+				r := rune(code)
+				if utf16.IsSurrogate(r) {
+					// Characters outside the BMP are escaped as a pair of surrogates
+					rest := p.s[p.i:]
+					if len(rest) < 6 && strings.HasPrefix(`\u`, rest[:min(len(rest), 2)]) {
+						// The low surrogate has not arrived yet
+						p.i = len(p.s)
+						return sb.String(), false
+					}
+					r = utf8.RuneError
+					if strings.HasPrefix(rest, `\u`) {
+						if low, err := strconv.ParseUint(rest[2:6], 16, 32); err == nil {
+							if pair := utf16.DecodeRune(rune(code), rune(low)); pair != utf8.RuneError {
+								r = pair
+								p.i += 6
+							}
+						}
+					}
+				}
+				sb.WriteRune(r)
+			default:
+				sb.WriteByte(esc)
+			}
+
+		default:
+			r, size := utf8.DecodeRuneInString(p.s[p.i:])
+			if r == utf8.RuneError && size <= 1 && !utf8.FullRuneInString(p.s[p.i:]) {
+				// Truncated multi-byte character at the end of the input
+				p.i = len(p.s)
+				return sb.String(), false
+			}
+			sb.WriteRune(r)
+			p.i += size
+		}
+	}
+
+	return sb.String(), false
+}
+
+func (p *parser) literal(word string, v any) (any, bool, bool) {
+	rest := p.s[p.i:]
+	if strings.HasPrefix(rest, word) {
+		p.i += len(word)
+		return v, true, true
+	}
+	if strings.HasPrefix(word, rest) {
+		// Truncated literal; drop it until it is complete
+		p.i = len(p.s)
+		return nil, false, false
+	}
+	return nil, false, false
+}
+
+func (p *parser) number() (any, bool, bool) {
+	start := p.i
+	for !p.eof() && strings.IndexByte("+-0123456789.eE", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	text := p.s[start:p.i]
+	complete := !p.eof()
+
+	// Trim characters that cannot end a number, e.g. "1." or "2e" mid-stream
+	trimmed := strings.TrimRight(text, "+-.eE")
+	if trimmed == "" {
+		return nil, false, complete
+	}
+	n, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return nil, false, false
+	}
+	return n, true, complete
+}
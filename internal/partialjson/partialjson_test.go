@@ -0,0 +1,49 @@
+package partialjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+		ok    bool
+	}{
+		{"empty", ``, nil, false},
+		{"complete object", `{"a": 1, "b": [true, null]}`, map[string]any{"a": 1.0, "b": []any{true, nil}}, true},
+		{"open object", `{"city": "Par`, map[string]any{"city": "Par"}, true},
+		{"incomplete key", `{"city": "Paris", "cou`, map[string]any{"city": "Paris"}, true},
+		{"key without value", `{"city": "Paris", "country":`, map[string]any{"city": "Paris"}, true},
+		{"open array", `[1, 2, 3`, []any{1.0, 2.0, 3.0}, true},
+		{"nested", `{"a": {"b": [1, {"c": "d`, map[string]any{"a": map[string]any{"b": []any{1.0, map[string]any{"c": "d"}}}}, true},
+		{"truncated number", `{"n": -12.`, map[string]any{"n": -12.0}, true},
+		{"truncated literal", `{"ok": tr`, map[string]any{}, true},
+		{"escapes", `"a\nb\t\"c\""`, "a\nb\t\"c\"", true},
+		{"truncated escape", `"ab\`, "ab", true},
+		{"unicode escape", `"caf\u00e9"`, "caf\u00e9", true},
+		{"truncated unicode escape", `"caf\u00`, "caf", true},
+		{"surrogate pair", `"\ud83d\ude00!"`, "\U0001F600!", true},
+		{"truncated surrogate pair", `"hi \ud83d\ude`, "hi ", true},
+		{"high surrogate at end", `"hi \ud83d`, "hi ", true},
+		{"lone high surrogate", `"\ud83dx"`, "\uFFFDx", true},
+		{"lone low surrogate", `"\ude00"`, "\uFFFD", true},
+		{"truncated multi-byte character", "\"caf\xc3", "caf", true},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.input)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse(%q) = %#v, %v, want %#v, %v", tt.name, tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseObject(t *testing.T) {
+	for _, input := range []string{``, `[1, 2]`, `"text"`, `{`} {
+		if got := ParseObject(input); !reflect.DeepEqual(got, map[string]any{}) {
+			t.Errorf("ParseObject(%q) = %#v, want an empty map", input, got)
+		}
+	}
+}
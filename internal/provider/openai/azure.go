@@ -0,0 +1,132 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/openai/openai-go/v3/option"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// DefaultAzureAPIVersion is the Azure OpenAI api-version used when none is configured
+const DefaultAzureAPIVersion = "2024-10-21"
+
+// deploymentRoutes are the Azure OpenAI routes scoped to a deployment
+var deploymentRoutes = map[string]bool{
+	"chat/completions": true,
+	"embeddings":       true,
+}
+
+// azureOptions point the client at an Azure OpenAI resource: requests go to
+// {endpoint}/openai/..., carry the api-version query parameter and
+// deployment-scoped routes are rewritten to /openai/deployments/{model}/...
+func azureOptions(config Config) []option.RequestOption {
+	return []option.RequestOption{
+		option.WithBaseURL(strings.TrimRight(config.URL, "/") + "/openai/"),
+		option.WithQuery("api-version", config.AzureAPIVersion),
+		option.WithMiddleware(routeToDeployment),
+	}
+}
+
+// routeToDeployment rewrites deployment-scoped paths using the model field of
+// the request body as the deployment name
+func routeToDeployment(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	i := strings.LastIndex(req.URL.Path, "/openai/")
+	if i < 0 || req.Body == nil {
+		return next(req)
+	}
+	route := req.URL.Path[i+len("/openai/"):]
+	if !deploymentRoutes[route] {
+		return next(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Model == "" {
+		return nil, fmt.Errorf("azure: request has no deployment (model)")
+	}
+
+	// Deployment names are a single path segment, so slashes in them are escaped
+	base := req.URL.Path[:i] + "/openai/deployments/"
+	req.URL.Path = base + payload.Model + "/" + route
+	req.URL.RawPath = (&url.URL{Path: base}).EscapedPath() + url.PathEscape(payload.Model) + "/" + route
+	return next(req)
+}
+
+// contentFilterResult is one category of an Azure content filter annotation
+type contentFilterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity"`
+}
+
+// filteredCategories returns the categories Azure flagged in a choice
+// (content_filter_results) or an error (innererror.content_filter_result),
+// e.g. "violence (medium)"
+func filteredCategories(raw string) []string {
+	var body struct {
+		Results    json.RawMessage `json:"content_filter_results"`
+		InnerError struct {
+			Result json.RawMessage `json:"content_filter_result"`
+		} `json:"innererror"`
+	}
+	if err := json.Unmarshal([]byte(raw), &body); err != nil {
+		return nil
+	}
+
+	var categories []string
+	for _, results := range []json.RawMessage{body.Results, body.InnerError.Result} {
+		var byCategory map[string]json.RawMessage
+		if err := json.Unmarshal(results, &byCategory); err != nil {
+			continue
+		}
+		for name, value := range byCategory {
+			// Blocklist entries are not shaped like the severity categories
+			var result contentFilterResult
+			if err := json.Unmarshal(value, &result); err != nil || !result.Filtered {
+				continue
+			}
+			if result.Severity != "" {
+				name += " (" + result.Severity + ")"
+			}
+			categories = append(categories, name)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// contentFilterMessage describes why a response finished with content_filter
+func contentFilterMessage(choiceJSON string) *string {
+	msg := "content filtered"
+	if categories := filteredCategories(choiceJSON); len(categories) > 0 {
+		msg += ": " + strings.Join(categories, ", ")
+	}
+	return &msg
+}
+
+// contentFilterError wraps an API error rejecting a prompt for its content
+// in types.ErrContentFiltered; other errors are returned unchanged
+func contentFilterError(err error) error {
+	apiErr, ok := apiError(err)
+	if !ok || apiErr.Code != "content_filter" {
+		return err
+	}
+	if categories := filteredCategories(apiErr.RawJSON()); len(categories) > 0 {
+		return fmt.Errorf("%w: %s: %w", types.ErrContentFiltered, strings.Join(categories, ", "), err)
+	}
+	return fmt.Errorf("%w: %w", types.ErrContentFiltered, err)
+}
@@ -0,0 +1,203 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+const completionJSON = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"created": 1,
+	"model": "gpt-4o",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 1, "total_tokens": 6}
+}`
+
+const filteredCompletionJSON = `{
+	"id": "chatcmpl-2",
+	"object": "chat.completion",
+	"created": 1,
+	"model": "gpt-4o",
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "content": ""},
+		"finish_reason": "content_filter",
+		"content_filter_results": {
+			"hate": {"filtered": false, "severity": "safe"},
+			"violence": {"filtered": true, "severity": "medium"}
+		}
+	}]
+}`
+
+const filteredPromptJSON = `{
+	"error": {
+		"code": "content_filter",
+		"message": "The response was filtered due to the prompt triggering content management policy.",
+		"status": 400,
+		"innererror": {
+			"code": "ResponsibleAIPolicyViolation",
+			"content_filter_result": {
+				"hate": {"filtered": true, "severity": "high"},
+				"self_harm": {"filtered": false, "severity": "safe"},
+				"custom_blocklists": [{"id": "words", "filtered": true}]
+			}
+		}
+	}
+}`
+
+// azureServer answers every request with status and body, recording the last request
+func azureServer(t *testing.T, status int, body string) (*httptest.Server, **http.Request) {
+	t.Helper()
+	var last *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r.Clone(context.Background())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+func azureProvider(url string) *Provider {
+	return New(Config{URL: url + "/", APIKey: "azure-key", AzureAPIVersion: DefaultAzureAPIVersion}, "prod-gpt4o", types.ProviderAzure)
+}
+
+var hello = types.Context{Messages: []types.Message{
+	types.UserMessage{Contents: []types.Content{types.TextContent{Text: "Hello"}}},
+}}
+
+func TestAzureRoutesToDeployment(t *testing.T) {
+	server, last := azureServer(t, http.StatusOK, completionJSON)
+
+	message, err := azureProvider(server.URL).Complete(context.Background(), hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Provider != types.ProviderAzure || message.StopReason != types.StopReasonStop {
+		t.Errorf("message = %+v", message)
+	}
+
+	req := *last
+	if want := "/openai/deployments/prod-gpt4o/chat/completions"; req.URL.Path != want {
+		t.Errorf("path = %s, want %s", req.URL.Path, want)
+	}
+	if version := req.URL.Query().Get("api-version"); version != DefaultAzureAPIVersion {
+		t.Errorf("api-version = %q", version)
+	}
+	if key := req.Header.Get("api-key"); key != "azure-key" {
+		t.Errorf("api-key header = %q", key)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		t.Errorf("Authorization header = %q, want none", auth)
+	}
+}
+
+func TestRouteToDeployment(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		wantPath string
+		wantErr  bool
+	}{
+		{"chat", "/openai/chat/completions", `{"model": "prod"}`, "/openai/deployments/prod/chat/completions", false},
+		{"embeddings", "/openai/embeddings", `{"model": "embed", "input": ["a"]}`, "/openai/deployments/embed/embeddings", false},
+		{"escaped deployment", "/openai/chat/completions", `{"model": "a/b"}`, "/openai/deployments/a%2Fb/chat/completions", false},
+		{"gateway prefix", "/gateway/openai/chat/completions", `{"model": "prod"}`, "/gateway/openai/deployments/prod/chat/completions", false},
+		{"resource route", "/openai/files", `{"model": "prod"}`, "/openai/files", false},
+		{"no model", "/openai/chat/completions", `{"messages": []}`, "", true},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodPost, "https://example.openai.azure.com"+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sent *http.Request
+		_, err = routeToDeployment(req, func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: routed a request without a deployment", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if got := sent.URL.EscapedPath(); got != tt.wantPath {
+			t.Errorf("%s: path = %s, want %s", tt.name, got, tt.wantPath)
+		}
+		body, _ := io.ReadAll(sent.Body)
+		if string(body) != tt.body {
+			t.Errorf("%s: body = %s, want it unchanged", tt.name, body)
+		}
+	}
+}
+
+func TestAzureCompletionFiltered(t *testing.T) {
+	server, _ := azureServer(t, http.StatusOK, filteredCompletionJSON)
+
+	message, err := azureProvider(server.URL).Complete(context.Background(), hello)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.ErrorMessage == nil || *message.ErrorMessage != "content filtered: violence (medium)" {
+		t.Errorf("ErrorMessage = %v", message.ErrorMessage)
+	}
+}
+
+func TestAzurePromptFiltered(t *testing.T) {
+	server, _ := azureServer(t, http.StatusBadRequest, filteredPromptJSON)
+
+	_, err := azureProvider(server.URL).Complete(context.Background(), hello)
+	if !errors.Is(err, types.ErrContentFiltered) {
+		t.Fatalf("error = %v, want ErrContentFiltered", err)
+	}
+	if status, ok := StatusCode(err); !ok || status != http.StatusBadRequest {
+		t.Errorf("status = %d, %v; the API error should stay wrapped", status, ok)
+	}
+	if !strings.Contains(err.Error(), ": hate (high):") {
+		t.Errorf("error = %v, want the filtered category", err)
+	}
+}
+
+func TestFilteredCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{"choice", `{"content_filter_results": {"violence": {"filtered": true, "severity": "medium"}, "sexual": {"filtered": true, "severity": "high"}}}`, []string{"sexual (high)", "violence (medium)"}},
+		{"error", `{"innererror": {"content_filter_result": {"jailbreak": {"filtered": true, "detected": true}}}}`, []string{"jailbreak"}},
+		{"nothing filtered", `{"content_filter_results": {"hate": {"filtered": false, "severity": "safe"}}}`, nil},
+		{"no annotations", `{"index": 0}`, nil},
+		{"invalid JSON", `{`, nil},
+	}
+	for _, tt := range tests {
+		if got := filteredCategories(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: filteredCategories = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestContentFilterErrorPassesOtherErrors(t *testing.T) {
+	err := &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("connection refused")}
+	if got := contentFilterError(err); got != error(err) {
+		t.Errorf("contentFilterError = %v, want the error unchanged", got)
+	}
+}
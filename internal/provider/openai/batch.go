@@ -0,0 +1,222 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	openaiSDK "github.com/openai/openai-go/v3"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// batchLine is one request in a Batch API input file
+type batchLine struct {
+	CustomID string                            `json:"custom_id"`
+	Method   string                            `json:"method"`
+	URL      string                            `json:"url"`
+	Body     openaiSDK.ChatCompletionNewParams `json:"body"`
+}
+
+// batchOutputLine is one line of a Batch API output or error file
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// BuildBatchInput encodes requests as Batch API JSONL using the same request
+// building as Complete
+func (p *Provider) BuildBatchInput(requests []types.BatchRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	seen := make(map[string]bool, len(requests))
+
+	for _, request := range requests {
+		if request.CustomID == "" {
+			return nil, fmt.Errorf("batch request is missing a custom ID")
+		}
+		if seen[request.CustomID] {
+			return nil, fmt.Errorf("duplicate batch custom ID %q", request.CustomID)
+		}
+		seen[request.CustomID] = true
+
+		if err := p.validate(request.Context); err != nil {
+			return nil, fmt.Errorf("batch request %s: %w", request.CustomID, err)
+		}
+
+		line, err := json.Marshal(batchLine{
+			CustomID: request.CustomID,
+			Method:   "POST",
+			URL:      string(openaiSDK.BatchNewParamsEndpointV1ChatCompletions),
+			Body:     buildParams(p.modelID, request.Context),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("batch request %s: %w", request.CustomID, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SubmitBatch uploads requests as a JSONL file and creates a batch job
+func (p *Provider) SubmitBatch(ctx context.Context, requests []types.BatchRequest) (types.BatchJob, error) {
+	logger := p.requestLogger()
+
+	input, err := p.BuildBatchInput(requests)
+	if err != nil {
+		return types.BatchJob{}, err
+	}
+
+	client, err := p.getClient()
+	if err != nil {
+		return types.BatchJob{}, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	file, err := client.Files.New(ctx, openaiSDK.FileNewParams{
+		File:    openaiSDK.File(bytes.NewReader(input), "batch.jsonl", "application/jsonl"),
+		Purpose: openaiSDK.FilePurposeBatch,
+	})
+	if err != nil {
+		logger.Error("batch upload failed", "error", err)
+		return types.BatchJob{}, fmt.Errorf("batch upload failed: %w", err)
+	}
+
+	batch, err := client.Batches.New(ctx, openaiSDK.BatchNewParams{
+		InputFileID:      file.ID,
+		Endpoint:         openaiSDK.BatchNewParamsEndpointV1ChatCompletions,
+		CompletionWindow: openaiSDK.BatchNewParamsCompletionWindow24h,
+	})
+	if err != nil {
+		logger.Error("batch creation failed", "input_file_id", file.ID, "error", err)
+		return types.BatchJob{}, fmt.Errorf("batch creation failed: %w", err)
+	}
+
+	logger.Info("batch submitted", "batch_id", batch.ID, "requests", len(requests))
+	return batchJobFromOpenAI(batch), nil
+}
+
+// BatchStatus fetches the current state of a batch job
+func (p *Provider) BatchStatus(ctx context.Context, batchID string) (types.BatchJob, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return types.BatchJob{}, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	batch, err := client.Batches.Get(ctx, batchID)
+	if err != nil {
+		return types.BatchJob{}, fmt.Errorf("batch status failed: %w", err)
+	}
+	return batchJobFromOpenAI(batch), nil
+}
+
+// CancelBatch requests cancellation of a batch job
+func (p *Provider) CancelBatch(ctx context.Context, batchID string) (types.BatchJob, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return types.BatchJob{}, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	batch, err := client.Batches.Cancel(ctx, batchID)
+	if err != nil {
+		return types.BatchJob{}, fmt.Errorf("batch cancel failed: %w", err)
+	}
+	return batchJobFromOpenAI(batch), nil
+}
+
+// BatchResults downloads the output and error files of a finished job
+func (p *Provider) BatchResults(ctx context.Context, job types.BatchJob) ([]types.BatchResult, error) {
+	client, err := p.getClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	results := []types.BatchResult{}
+	for _, fileID := range []string{job.OutputFileID, job.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+
+		response, err := client.Files.Content(ctx, fileID)
+		if err != nil {
+			return nil, fmt.Errorf("batch results download failed: %w", err)
+		}
+		fileResults, err := p.parseBatchOutput(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("batch results file %s: %w", fileID, err)
+		}
+		results = append(results, fileResults...)
+	}
+
+	return results, nil
+}
+
+func (p *Provider) parseBatchOutput(r io.Reader) ([]types.BatchResult, error) {
+	results := []types.BatchResult{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var line batchOutputLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, err
+		}
+
+		result := types.BatchResult{CustomID: line.CustomID}
+		switch {
+		case line.Error != nil:
+			result.Error = fmt.Sprintf("%s: %s", line.Error.Code, line.Error.Message)
+
+		case line.Response == nil:
+			result.Error = "missing response"
+
+		case line.Response.StatusCode >= 400:
+			result.Error = fmt.Sprintf("status %d: %s", line.Response.StatusCode, line.Response.Body)
+
+		default:
+			var completion openaiSDK.ChatCompletion
+			if err := json.Unmarshal(line.Response.Body, &completion); err != nil {
+				result.Error = fmt.Sprintf("decode response: %v", err)
+				break
+			}
+			result.Message = p.messageFromCompletion(&completion, defaultAudioFormat)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, scanner.Err()
+}
+
+func batchJobFromOpenAI(batch *openaiSDK.Batch) types.BatchJob {
+	job := types.BatchJob{
+		ID:           batch.ID,
+		Status:       types.BatchStatus(batch.Status),
+		InputFileID:  batch.InputFileID,
+		OutputFileID: batch.OutputFileID,
+		ErrorFileID:  batch.ErrorFileID,
+		Total:        int(batch.RequestCounts.Total),
+		Completed:    int(batch.RequestCounts.Completed),
+		Failed:       int(batch.RequestCounts.Failed),
+		CreatedAt:    time.Unix(batch.CreatedAt, 0),
+	}
+	for _, batchErr := range batch.Errors.Data {
+		job.Errors = append(job.Errors, fmt.Sprintf("%s: %s", batchErr.Code, batchErr.Message))
+	}
+	return job
+}
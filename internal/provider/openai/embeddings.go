@@ -0,0 +1,207 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	openaiSDK "github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/rahulSailesh-shah/go-pi-ai/catalog"
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// defaultEmbeddingBatchSize is the OpenAI per-request input limit
+const defaultEmbeddingBatchSize = 2048
+
+// Embedder generates embeddings through an OpenAI-compatible /embeddings endpoint
+type Embedder struct {
+	config       Config
+	modelID      string
+	providerType types.ModelProvider
+	client       *openaiSDK.Client
+	logger       *slog.Logger
+	mu           sync.Mutex
+}
+
+func NewEmbedder(config Config, modelID string, providerType types.ModelProvider) *Embedder {
+	return &Embedder{
+		config:       config,
+		modelID:      modelID,
+		providerType: providerType,
+		logger:       logging.OrDiscard(config.Logger),
+	}
+}
+
+func (e *Embedder) Model() string {
+	return e.modelID
+}
+
+func (e *Embedder) ProviderType() types.ModelProvider {
+	return e.providerType
+}
+
+func (e *Embedder) getClient() (*openaiSDK.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	client, err := newClient(e.config)
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+	return e.client, nil
+}
+
+// Embed returns one embedding per input, in input order, splitting the
+// inputs into batches that respect the model's per-request limit
+func (e *Embedder) Embed(ctx context.Context, inputs []string, opts types.EmbedOptions) ([][]float32, types.Usage, error) {
+	logger := e.logger.With(
+		"request_id", newRequestID(),
+		"provider", string(e.providerType),
+		"model", e.modelID,
+	)
+	started := time.Now()
+
+	client, err := e.getClient()
+	if err != nil {
+		return nil, types.Usage{}, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	batchSize := e.batchSize(opts)
+	embeddings := make([][]float32, 0, len(inputs))
+	usage := types.Usage{}
+
+	for start := 0; start < len(inputs); start += batchSize {
+		end := min(start+batchSize, len(inputs))
+
+		batch, batchUsage, err := e.embedBatch(ctx, client, inputs[start:end], opts)
+		if err != nil {
+			logger.Error("embedding failed", "latency", time.Since(started), "batch_start", start, "error", err)
+			return nil, types.Usage{}, fmt.Errorf("embedding failed: %w", err)
+		}
+
+		embeddings = append(embeddings, batch...)
+		usage.InputTokens += batchUsage.InputTokens
+		usage.TotalTokens += batchUsage.TotalTokens
+	}
+
+	logger.Info("embedding completed",
+		"latency", time.Since(started),
+		"inputs", len(inputs),
+		"input_tokens", usage.InputTokens,
+	)
+
+	return embeddings, usage, nil
+}
+
+func (e *Embedder) batchSize(opts types.EmbedOptions) int {
+	if opts.BatchSize > 0 {
+		return opts.BatchSize
+	}
+	if info, ok := catalog.Lookup(types.Model{Provider: e.providerType, ID: e.modelID}); ok && info.MaxEmbeddingInputs > 0 {
+		return info.MaxEmbeddingInputs
+	}
+	return defaultEmbeddingBatchSize
+}
+
+func (e *Embedder) embedBatch(ctx context.Context, client *openaiSDK.Client, inputs []string, opts types.EmbedOptions) ([][]float32, types.Usage, error) {
+	encoding := opts.Encoding
+	if encoding == "" {
+		encoding = types.EmbeddingEncodingBase64
+	}
+
+	params := openaiSDK.EmbeddingNewParams{
+		Model:          e.modelID,
+		Input:          openaiSDK.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+		EncodingFormat: openaiSDK.EmbeddingNewParamsEncodingFormat(encoding),
+	}
+	if opts.Dimensions > 0 {
+		params.Dimensions = openaiSDK.Int(int64(opts.Dimensions))
+	}
+
+	requestOpts := []option.RequestOption{}
+	if opts.InputType != "" {
+		requestOpts = append(requestOpts, option.WithJSONSet("input_type", opts.InputType))
+	}
+
+	// The SDK decodes embeddings as floats, so base64 payloads are read from the raw body
+	var raw []byte
+	requestOpts = append(requestOpts, option.WithResponseBodyInto(&raw))
+	if _, err := client.Embeddings.New(ctx, params, requestOpts...); err != nil {
+		return nil, types.Usage{}, err
+	}
+
+	var response embeddingResponse
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, types.Usage{}, fmt.Errorf("decode embeddings: %w", err)
+	}
+	if len(response.Data) != len(inputs) {
+		return nil, types.Usage{}, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(response.Data))
+	}
+
+	embeddings := make([][]float32, len(inputs))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(inputs) {
+			return nil, types.Usage{}, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		vector, err := decodeEmbedding(item.Embedding)
+		if err != nil {
+			return nil, types.Usage{}, err
+		}
+		embeddings[item.Index] = vector
+	}
+
+	return embeddings, types.Usage{
+		InputTokens: int(response.Usage.PromptTokens),
+		TotalTokens: int(response.Usage.TotalTokens),
+	}, nil
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int64 `json:"prompt_tokens"`
+		TotalTokens  int64 `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// decodeEmbedding accepts either a JSON float array or a base64 string of
+// little-endian float32 values
+func decodeEmbedding(raw json.RawMessage) ([]float32, error) {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode embedding: %w", err)
+		}
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("decode embedding: %d bytes is not a float32 array", len(data))
+		}
+		vector := make([]float32, len(data)/4)
+		for i := range vector {
+			vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+		}
+		return vector, nil
+	}
+
+	var vector []float32
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, fmt.Errorf("decode embedding: %w", err)
+	}
+	return vector, nil
+}
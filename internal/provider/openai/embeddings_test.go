@@ -0,0 +1,135 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func encodeFloats(values ...float32) string {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// embeddingServer embeds each numeric input n as [n, -n], answering in reverse
+// index order, and records the size of every request
+func embeddingServer(t *testing.T) (*httptest.Server, *[]int) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		batches []int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Input          []string `json:"input"`
+			EncodingFormat string   `json:"encoding_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		batches = append(batches, len(request.Input))
+		mu.Unlock()
+
+		var data []string
+		for i := len(request.Input) - 1; i >= 0; i-- {
+			n, _ := strconv.ParseFloat(request.Input[i], 32)
+			embedding := fmt.Sprintf("[%v, %v]", n, -n)
+			if request.EncodingFormat == "base64" {
+				embedding = strconv.Quote(encodeFloats(float32(n), float32(-n)))
+			}
+			data = append(data, fmt.Sprintf(`{"object": "embedding", "index": %d, "embedding": %s}`, i, embedding))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"object": "list", "model": "embed", "data": [%s], "usage": {"prompt_tokens": %d, "total_tokens": %d}}`,
+			strings.Join(data, ","), len(request.Input), len(request.Input))
+	}))
+	t.Cleanup(server.Close)
+	return server, &batches
+}
+
+func numbers(n int) ([]string, [][]float32) {
+	inputs := make([]string, n)
+	want := make([][]float32, n)
+	for i := range inputs {
+		inputs[i] = strconv.Itoa(i)
+		want[i] = []float32{float32(i), -float32(i)}
+	}
+	return inputs, want
+}
+
+func TestEmbed(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       string
+		provider    types.ModelProvider
+		inputs      int
+		opts        types.EmbedOptions
+		wantBatches []int
+	}{
+		{"base64", "embed", types.ProviderOpenAI, 3, types.EmbedOptions{}, []int{3}},
+		{"float", "embed", types.ProviderOpenAI, 3, types.EmbedOptions{Encoding: types.EmbeddingEncodingFloat}, []int{3}},
+		{"batch size", "embed", types.ProviderOpenAI, 5, types.EmbedOptions{BatchSize: 2}, []int{2, 2, 1}},
+		{"catalog limit", "nvidia/nv-embedqa-e5-v5", types.ProviderNvidia, 120, types.EmbedOptions{}, []int{50, 50, 20}},
+	}
+	for _, tt := range tests {
+		server, batches := embeddingServer(t)
+		embedder := NewEmbedder(Config{URL: server.URL, APIKey: "test"}, tt.model, tt.provider)
+		inputs, want := numbers(tt.inputs)
+
+		got, usage, err := embedder.Embed(context.Background(), inputs, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: embeddings = %v, want %v in input order", tt.name, got, want)
+		}
+		if !reflect.DeepEqual(*batches, tt.wantBatches) {
+			t.Errorf("%s: batches = %v, want %v", tt.name, *batches, tt.wantBatches)
+		}
+		if usage.InputTokens != tt.inputs {
+			t.Errorf("%s: input tokens = %d, want usage summed over batches", tt.name, usage.InputTokens)
+		}
+	}
+}
+
+func TestDecodeEmbedding(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []float32
+		wantErr bool
+	}{
+		{"floats", `[0.5, -1.25]`, []float32{0.5, -1.25}, false},
+		{"base64", strconv.Quote(encodeFloats(0.5, -1.25)), []float32{0.5, -1.25}, false},
+		{"invalid base64", `"not base64!"`, nil, true},
+		{"truncated base64", strconv.Quote(base64.StdEncoding.EncodeToString([]byte{1, 2, 3})), nil, true},
+		{"wrong type", `{"a": 1}`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeEmbedding(json.RawMessage(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: decodeEmbedding error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: decodeEmbedding = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package openai
+
+import (
+	"errors"
+
+	openaiSDK "github.com/openai/openai-go/v3"
+)
+
+// StatusCode returns the HTTP status of an API error returned by the SDK
+func StatusCode(err error) (int, bool) {
+	if apiErr, ok := apiError(err); ok {
+		return apiErr.StatusCode, true
+	}
+	return 0, false
+}
+
+func apiError(err error) (*openaiSDK.Error, bool) {
+	var apiErr *openaiSDK.Error
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
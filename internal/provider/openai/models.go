@@ -0,0 +1,31 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// ListModels returns the model IDs served by the endpoint's /models listing
+func ListModels(ctx context.Context, config Config) ([]string, error) {
+	// Azure lists base models, not the deployments requests are addressed to
+	if config.AzureAPIVersion != "" {
+		return nil, fmt.Errorf("%w: Azure OpenAI deployment discovery", types.ErrNotSupported)
+	}
+
+	client, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ids := []string{}
+	pager := client.Models.ListAutoPaging(ctx)
+	for pager.Next() {
+		ids = append(ids, pager.Current().ID)
+	}
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("list models failed: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,48 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"object": "list", "data": [
+			{"id": "gpt-4o", "object": "model", "created": 1, "owned_by": "openai"},
+			{"id": "ft:gpt-4o:acme", "object": "model", "created": 2, "owned_by": "acme"}
+		]}`)
+	}))
+	defer server.Close()
+
+	ids, err := ListModels(context.Background(), Config{URL: server.URL, APIKey: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gpt-4o", "ft:gpt-4o:acme"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ListModels = %v, want %v", ids, want)
+	}
+}
+
+func TestListModelsErrors(t *testing.T) {
+	server, _ := azureServer(t, http.StatusUnauthorized, `{"error": {"message": "invalid key"}}`)
+
+	if _, err := ListModels(context.Background(), Config{URL: server.URL, APIKey: "bad"}); err == nil {
+		t.Error("ListModels succeeded on an error response")
+	}
+	_, err := ListModels(context.Background(), Config{URL: server.URL, APIKey: "azure", AzureAPIVersion: DefaultAzureAPIVersion})
+	if !errors.Is(err, types.ErrNotSupported) {
+		t.Errorf("Azure ListModels error = %v, want ErrNotSupported", err)
+	}
+}
@@ -2,19 +2,41 @@ package openai
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	openaiSDK "github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/shared"
+	"github.com/rahulSailesh-shah/go-pi-ai/catalog"
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/partialjson"
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
 	"github.com/rahulSailesh-shah/go-pi-ai/types"
 )
 
 type Config struct {
 	URL    string
 	APIKey string
+	// APIKeyFunc, when set, is called for every request instead of using APIKey
+	APIKeyFunc func(ctx context.Context) (string, error)
+	// Logger receives request diagnostics; nil disables logging
+	Logger *slog.Logger
+	// HTTPClient overrides the client used for API calls, e.g. to record or replay traffic
+	HTTPClient *http.Client
+	// Headers are sent with every request
+	Headers map[string]string
+	// Timeout bounds each request attempt; zero uses the SDK default
+	Timeout time.Duration
+	// AzureAPIVersion, when set, treats URL as an Azure OpenAI resource
+	// endpoint: models name deployments and the key is sent as api-key
+	AzureAPIVersion string
 }
 
 type Provider struct {
@@ -22,6 +44,7 @@ type Provider struct {
 	modelID      string
 	providerType types.ModelProvider
 	client       *openaiSDK.Client
+	logger       *slog.Logger
 	mu           sync.Mutex
 }
 
@@ -30,6 +53,7 @@ func New(config Config, modelID string, providerType types.ModelProvider) *Provi
 		config:       config,
 		modelID:      modelID,
 		providerType: providerType,
+		logger:       logging.OrDiscard(config.Logger),
 	}
 }
 
@@ -49,39 +73,147 @@ func (p *Provider) getClient() (*openaiSDK.Client, error) {
 		return p.client, nil
 	}
 
+	client, err := newClient(p.config)
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	return p.client, nil
+}
+
+// newClient creates an SDK client from config
+func newClient(config Config) (*openaiSDK.Client, error) {
 	// Validate config
-	if p.config.APIKey == "" {
+	if config.APIKey == "" && config.APIKeyFunc == nil {
 		return nil, fmt.Errorf("API key is required")
 	}
 
 	opts := []option.RequestOption{}
-	opts = append(opts, option.WithAPIKey(p.config.APIKey))
+	azure := config.AzureAPIVersion != ""
+	switch {
+	case config.APIKeyFunc != nil:
+		opts = append(opts, option.WithMiddleware(authorize(config.APIKeyFunc, azure)))
+	case azure:
+		opts = append(opts, option.WithMiddleware(authorize(staticKey(config.APIKey), azure)))
+	default:
+		opts = append(opts, option.WithAPIKey(config.APIKey))
+	}
+
+	if azure {
+		opts = append(opts, azureOptions(config)...)
+	} else if config.URL != "" {
+		opts = append(opts, option.WithBaseURL(config.URL))
+	}
+
+	if config.HTTPClient != nil {
+		opts = append(opts, option.WithHTTPClient(config.HTTPClient))
+	}
+
+	for name, value := range config.Headers {
+		opts = append(opts, option.WithHeader(name, value))
+	}
 
-	if p.config.URL != "" {
-		opts = append(opts, option.WithBaseURL(p.config.URL))
+	if config.Timeout > 0 {
+		opts = append(opts, option.WithRequestTimeout(config.Timeout))
 	}
 
 	client := openaiSDK.NewClient(opts...)
-	p.client = &client
-	return p.client, nil
+	return &client, nil
+}
+
+// authorize sets the bearer token of each request from apiKey, or the
+// api-key header for Azure
+func authorize(apiKey func(ctx context.Context) (string, error), azure bool) option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		key, err := apiKey(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		if azure {
+			// Drop any bearer token the SDK picked up from OPENAI_API_KEY
+			req.Header.Del("Authorization")
+			req.Header.Set("api-key", key)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		return next(req)
+	}
+}
+
+func staticKey(key string) func(ctx context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		return key, nil
+	}
 }
 
 func (p *Provider) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
 	stream := types.NewAssistantMessageEventStream()
 
 	go func() {
+		logger := p.requestLogger()
+		started := time.Now()
+
 		output := types.AssistantMessage{
 			Contents:  []types.Content{},
 			Provider:  p.providerType,
-			Timestamp: time.Now(),
+			Timestamp: started,
+		}
+
+		// finish emits the terminal event and hands the result to the consumer
+		finish := func(err error) {
+			if err != nil {
+				output.StopReason = types.StopReasonError
+				if ctx.Err() != nil {
+					output.StopReason = types.StopReasonAborted
+				}
+				errMsg := err.Error()
+				output.ErrorMessage = &errMsg
+				logger.Error("stream failed",
+					"latency", time.Since(started),
+					"stop_reason", string(output.StopReason),
+					"error", err,
+				)
+				stream.Events <- types.EventError{
+					Reason: output.StopReason,
+					Error:  output,
+				}
+			} else {
+				p.logCompletion(logger, "stream completed", started, output)
+				stream.Events <- types.EventDone{
+					Reason:  output.StopReason,
+					Message: output,
+				}
+			}
+
+			stream.Result <- output
+			stream.Err <- err
+			stream.Close()
+		}
+
+		if err := p.validate(conversation); err != nil {
+			finish(err)
+			return
+		}
+
+		format, err := streamAudioFormat(conversation)
+		if err != nil {
+			finish(err)
+			return
 		}
 
 		params := buildParams(p.modelID, conversation)
+		params.StreamOptions = openaiSDK.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openaiSDK.Bool(true),
+		}
+		if conversation.Audio != nil {
+			params.Audio.Format = openaiSDK.ChatCompletionAudioParamFormat(format)
+		}
+		p.logRequest(logger, params)
 
 		// Get or create client lazily
 		client, err := p.getClient()
 		if err != nil {
-			stream.Err <- fmt.Errorf("failed to create client: %w", err)
+			finish(fmt.Errorf("failed to create client: %w", err))
 			return
 		}
 
@@ -94,51 +226,91 @@ func (p *Provider) Stream(ctx context.Context, conversation types.Context) types
 		currentContentIndex := -1
 		currentBlockType := ""
 
-		for openaiStream.Next() {
-			chunk := openaiStream.Current()
+		// Tool calls are tracked per OpenAI delta index so parallel calls each
+		// get their own content block; openToolCalls keeps content index order.
+		toolCalls := map[int64]*toolCallState{}
+		openToolCalls := []*toolCallState{}
 
-			if !acc.AddChunk(chunk) {
-				// Handle error
-				stream.Err <- fmt.Errorf("failed to add chunk")
+		var text strings.Builder
+		endText := func() {
+			if currentBlockType != "text" {
 				return
 			}
-
-			// Update stop reason
-			if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "" {
-				output.StopReason = stopReasonFromOpenAI(string(chunk.Choices[0].FinishReason))
+			content := text.String()
+			stream.Events <- types.EventTextEnd{
+				ContentIndex: currentContentIndex,
+				Content:      content,
+				Partial:      output,
 			}
+			currentBlockType = ""
+			output.Contents = append(output.Contents, types.TextContent{
+				Text: content,
+			})
+			text.Reset()
+		}
 
-			// Handle finished content block
-			if content, ok := acc.JustFinishedContent(); ok && content != "" {
-				stream.Events <- types.EventTextEnd{
-					ContentIndex: currentContentIndex,
-					Content:      content,
-					Partial:      output,
-				}
+		var audio *audioState
+		endAudio := func() {
+			if audio == nil {
+				return
+			}
+			content := audio.audio()
+			stream.Events <- types.EventAudioEnd{
+				ContentIndex: audio.contentIndex,
+				Content:      content,
+				Partial:      output,
+			}
+			output.Contents = append(output.Contents, content)
+			audio = nil
+			if currentBlockType == "audio" {
 				currentBlockType = ""
-				output.Contents = append(output.Contents, types.TextContent{
-					Text: content,
-				})
 			}
+		}
 
-			// Handle finished tool call block
-			if toolCall, ok := acc.JustFinishedToolCall(); ok {
-				args := make(map[string]any)
-				if err := json.Unmarshal([]byte(toolCall.Arguments), &args); err != nil {
-					args = make(map[string]interface{})
-				}
-				tc := types.ToolCall{
-					ID:        toolCall.ID,
-					Name:      toolCall.Name,
-					Arguments: args,
-				}
+		endToolCalls := func() {
+			for _, tc := range openToolCalls {
+				call := tc.toolCall()
 				stream.Events <- types.EventToolcallEnd{
-					ContentIndex: currentContentIndex,
-					ToolCall:     tc,
+					ContentIndex: tc.contentIndex,
+					ToolCall:     call,
 					Partial:      output,
 				}
+				output.Contents = append(output.Contents, call)
+			}
+			clear(toolCalls)
+			openToolCalls = openToolCalls[:0]
+			if currentBlockType == "toolCall" {
 				currentBlockType = ""
-				output.Contents = append(output.Contents, tc)
+			}
+		}
+
+		for openaiStream.Next() {
+			chunk := openaiStream.Current()
+
+			// Azure sends content filter annotations in chunks without an ID
+			if p.config.AzureAPIVersion != "" && chunk.ID == "" {
+				updateStopReason(&output, chunk)
+				continue
+			}
+
+			if acc.ID == "" && chunk.ID != "" {
+				logger = logger.With("response_id", chunk.ID)
+			}
+
+			if !acc.AddChunk(chunk) {
+				finish(fmt.Errorf("failed to add chunk"))
+				return
+			}
+
+			if chunk.JSON.Usage.Valid() {
+				output.Usage = usageFromOpenAI(chunk.Usage)
+			}
+
+			updateStopReason(&output, chunk)
+
+			// Handle finished content block
+			if content, ok := acc.JustFinishedContent(); ok && content != "" {
+				endText()
 			}
 
 			if len(chunk.Choices) == 0 {
@@ -149,8 +321,10 @@ func (p *Provider) Stream(ctx context.Context, conversation types.Context) types
 
 			// Handle text delta
 			if delta.Content != "" {
-				fmt.Println(".")
 				if currentBlockType != "text" {
+					// Text after tool calls means the model has moved on from them
+					endAudio()
+					endToolCalls()
 					currentContentIndex++
 					currentBlockType = "text"
 					stream.Events <- types.EventTextStart{
@@ -158,6 +332,7 @@ func (p *Provider) Stream(ctx context.Context, conversation types.Context) types
 						Partial:      output,
 					}
 				}
+				text.WriteString(delta.Content)
 				stream.Events <- types.EventTextDelta{
 					ContentIndex: currentContentIndex,
 					Delta:        delta.Content,
@@ -165,72 +340,254 @@ func (p *Provider) Stream(ctx context.Context, conversation types.Context) types
 				}
 			}
 
-			// Handle tool call delta
-			if len(delta.ToolCalls) > 0 {
-				for _, toolCallDelta := range delta.ToolCalls {
-					if currentBlockType != "toolCall" {
-						currentContentIndex++
-						currentBlockType = "toolCall"
-						stream.Events <- types.EventToolcallStart{
-							ContentIndex: currentContentIndex,
-							Partial:      output,
-						}
+			// Handle audio deltas
+			if chunkAudio, ok := parseAudioDelta(delta.RawJSON()); ok {
+				if audio == nil {
+					// The accumulator does not track audio, so it never ends the text block
+					endText()
+					currentContentIndex++
+					currentBlockType = "audio"
+					audio = &audioState{
+						contentIndex: currentContentIndex,
+						format:       format,
+					}
+					stream.Events <- types.EventAudioStart{
+						ContentIndex: audio.contentIndex,
+						Partial:      output,
+					}
+				}
+				if chunkAudio.ID != "" {
+					audio.id = chunkAudio.ID
+				}
+				if chunkAudio.ExpiresAt != 0 {
+					audio.expiresAt = chunkAudio.ExpiresAt
+				}
+				audio.data.WriteString(chunkAudio.Data)
+				audio.transcript.WriteString(chunkAudio.Transcript)
+				if chunkAudio.Data != "" || chunkAudio.Transcript != "" {
+					stream.Events <- types.EventAudioDelta{
+						ContentIndex: audio.contentIndex,
+						Data:         chunkAudio.Data,
+						Transcript:   chunkAudio.Transcript,
+						Partial:      output,
+					}
+				}
+			}
+
+			// Handle tool call deltas
+			for _, toolCallDelta := range delta.ToolCalls {
+				tc, ok := toolCalls[toolCallDelta.Index]
+
+				// Some servers reuse index 0 for parallel calls; a new ID starts a new call
+				if ok && toolCallDelta.ID != "" && tc.id != "" && toolCallDelta.ID != tc.id {
+					ok = false
+				}
+
+				if !ok {
+					currentContentIndex++
+					currentBlockType = "toolCall"
+					tc = &toolCallState{
+						contentIndex: currentContentIndex,
+						id:           toolCallDelta.ID,
+						name:         toolCallDelta.Function.Name,
+					}
+					toolCalls[toolCallDelta.Index] = tc
+					openToolCalls = append(openToolCalls, tc)
+					stream.Events <- types.EventToolcallStart{
+						ContentIndex: tc.contentIndex,
+						ID:           tc.id,
+						Name:         tc.name,
+						Partial:      output,
+					}
+				} else {
+					if tc.id == "" {
+						tc.id = toolCallDelta.ID
 					}
-					if toolCallDelta.Function.Arguments != "" {
-						stream.Events <- types.EventToolcallDelta{
-							ContentIndex: currentContentIndex,
-							Delta:        toolCallDelta.Function.Arguments,
-							Partial:      output,
-						}
+					if tc.name == "" {
+						tc.name = toolCallDelta.Function.Name
+					}
+				}
+
+				if toolCallDelta.Function.Arguments != "" {
+					tc.arguments.WriteString(toolCallDelta.Function.Arguments)
+					stream.Events <- types.EventToolcallDelta{
+						ContentIndex: tc.contentIndex,
+						ID:           tc.id,
+						Name:         tc.name,
+						Delta:        toolCallDelta.Function.Arguments,
+						Arguments:    partialjson.ParseObject(tc.arguments.String()),
+						Partial:      output,
 					}
 				}
 			}
+
+			if chunk.Choices[0].FinishReason != "" {
+				endText()
+				endAudio()
+				endToolCalls()
+			}
 		}
 
 		if err := openaiStream.Err(); err != nil {
-			stream.Err <- err
+			finish(contentFilterError(err))
 			return
 		}
 
 		if ctx.Err() != nil {
-			stream.Err <- ctx.Err()
+			finish(ctx.Err())
 			return
 		}
 
-		stream.Events <- types.EventDone{
-			Reason:  output.StopReason,
-			Message: output,
-		}
-
-		stream.Result <- output
-		stream.Close()
+		endText()
+		endAudio()
+		endToolCalls()
+		finish(nil)
 	}()
 
 	return stream
 }
 
+// toolCallState accumulates one streamed tool call
+type toolCallState struct {
+	contentIndex int
+	id           string
+	name         string
+	arguments    strings.Builder
+}
+
+func (tc *toolCallState) toolCall() types.ToolCall {
+	args := make(map[string]any)
+	if err := json.Unmarshal([]byte(tc.arguments.String()), &args); err != nil {
+		args = make(map[string]any)
+	}
+	return types.ToolCall{
+		ID:           tc.id,
+		Name:         tc.name,
+		Arguments:    args,
+		RawArguments: tc.arguments.String(),
+	}
+}
+
+// audioState accumulates streamed audio output
+type audioState struct {
+	contentIndex int
+	format       string
+	id           string
+	expiresAt    int64
+	data         strings.Builder
+	transcript   strings.Builder
+}
+
+// audioDelta is the audio field of a streamed delta, which the SDK does not model
+type audioDelta struct {
+	ID         string `json:"id"`
+	Data       string `json:"data"`
+	Transcript string `json:"transcript"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+// parseAudioDelta extracts the audio field from a raw streamed delta
+func parseAudioDelta(raw string) (audioDelta, bool) {
+	if !strings.Contains(raw, `"audio"`) {
+		return audioDelta{}, false
+	}
+	var delta struct {
+		Audio *audioDelta `json:"audio"`
+	}
+	if err := json.Unmarshal([]byte(raw), &delta); err != nil || delta.Audio == nil {
+		return audioDelta{}, false
+	}
+	return *delta.Audio, true
+}
+
+func (a *audioState) audio() types.AudioContent {
+	return types.AudioContent{
+		Format:     a.format,
+		Data:       a.data.String(),
+		Transcript: a.transcript.String(),
+		ID:         a.id,
+		ExpiresAt:  unixTime(a.expiresAt),
+	}
+}
+
+// streamAudioFormat returns the audio output format of a streamed request
+func streamAudioFormat(conversation types.Context) (string, error) {
+	if conversation.Audio == nil {
+		return "", nil
+	}
+	format := orDefault(conversation.Audio.Format, streamedAudioFormat)
+	if format != streamedAudioFormat {
+		return "", fmt.Errorf("%w: streaming audio output as %s; only %s can be streamed", types.ErrNotSupported, format, streamedAudioFormat)
+	}
+	return format, nil
+}
+
+func audioFormat(conversation types.Context) string {
+	if conversation.Audio == nil {
+		return defaultAudioFormat
+	}
+	return orDefault(conversation.Audio.Format, defaultAudioFormat)
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+// validate checks attachments against the catalog limits for this model
+func (p *Provider) validate(conversation types.Context) error {
+	return catalog.Validate(types.Model{Provider: p.providerType, ID: p.modelID}, conversation)
+}
+
 func (p *Provider) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	logger := p.requestLogger()
+	started := time.Now()
+
+	if err := p.validate(conversation); err != nil {
+		logger.Error("completion failed", "error", err)
+		return types.AssistantMessage{}, err
+	}
+
 	params := buildParams(p.modelID, conversation)
+	p.logRequest(logger, params)
 
 	client, err := p.getClient()
 	if err != nil {
+		logger.Error("completion failed", "error", err)
 		return types.AssistantMessage{}, fmt.Errorf("failed to create client: %w", err)
 	}
 
 	response, err := client.Chat.Completions.New(ctx, params)
 	if err != nil {
-		return types.AssistantMessage{}, fmt.Errorf("completion failed: %w", err)
+		logger.Error("completion failed", "latency", time.Since(started), "error", err)
+		return types.AssistantMessage{}, fmt.Errorf("completion failed: %w", contentFilterError(err))
 	}
 
+	output := p.messageFromCompletion(response, audioFormat(conversation))
+
+	p.logCompletion(logger.With("response_id", response.ID), "completion finished", started, output)
+
+	return output, nil
+}
+
+// messageFromCompletion converts a non-streaming response; format is the
+// requested audio output format
+func (p *Provider) messageFromCompletion(completion *openaiSDK.ChatCompletion, format string) types.AssistantMessage {
 	output := types.AssistantMessage{
 		Provider:  p.providerType,
 		Timestamp: time.Now(),
 		Contents:  []types.Content{},
+		Usage:     usageFromOpenAI(completion.Usage),
 	}
 
-	if len(response.Choices) > 0 {
-		output.StopReason = stopReasonFromOpenAI(string(response.Choices[0].FinishReason))
-		msg := response.Choices[0].Message
+	if len(completion.Choices) > 0 {
+		output.StopReason = stopReasonFromOpenAI(string(completion.Choices[0].FinishReason))
+		if completion.Choices[0].FinishReason == "content_filter" {
+			output.ErrorMessage = contentFilterMessage(completion.Choices[0].RawJSON())
+		}
+		msg := completion.Choices[0].Message
 
 		if msg.Content != "" {
 			output.Contents = append(output.Contents, types.TextContent{
@@ -238,32 +595,137 @@ func (p *Provider) Complete(ctx context.Context, conversation types.Context) (ty
 			})
 		}
 
+		if msg.Audio.ID != "" {
+			output.Contents = append(output.Contents, types.AudioContent{
+				Format:     format,
+				Data:       msg.Audio.Data,
+				Transcript: msg.Audio.Transcript,
+				ID:         msg.Audio.ID,
+				ExpiresAt:  unixTime(msg.Audio.ExpiresAt),
+			})
+		}
+
 		for _, tc := range msg.ToolCalls {
 			args := make(map[string]any)
 			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
 				args = make(map[string]any)
 			}
 			output.Contents = append(output.Contents, types.ToolCall{
-				ID:        tc.ID,
-				Name:      tc.Function.Name,
-				Arguments: args,
+				ID:           tc.ID,
+				Name:         tc.Function.Name,
+				Arguments:    args,
+				RawArguments: tc.Function.Arguments,
 			})
 		}
 	}
 
-	return output, nil
+	return output
+}
+
+// requestLogger returns a logger scoped to a single model call
+func (p *Provider) requestLogger() *slog.Logger {
+	return p.logger.With(
+		"request_id", newRequestID(),
+		"provider", string(p.providerType),
+		"model", p.modelID,
+	)
+}
+
+func (p *Provider) logRequest(logger *slog.Logger, params openaiSDK.ChatCompletionNewParams) {
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		logger.Debug("sending request", "payload_error", err)
+		return
+	}
+	logger.Debug("sending request", "payload", string(payload))
+}
+
+func (p *Provider) logCompletion(logger *slog.Logger, msg string, started time.Time, output types.AssistantMessage) {
+	logger.Info(msg,
+		"latency", time.Since(started),
+		"stop_reason", string(output.StopReason),
+		"input_tokens", output.Usage.InputTokens,
+		"output_tokens", output.Usage.OutputTokens,
+	)
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
 }
 
 func buildParams(modelID string, conversation types.Context) openaiSDK.ChatCompletionNewParams {
 	messages := buildMessages(conversation)
 	tools := buildTools(conversation.Tools)
 
-	return openaiSDK.ChatCompletionNewParams{
+	params := openaiSDK.ChatCompletionNewParams{
 		Messages: messages,
 		Model:    modelID,
 		Tools:    tools,
 		Seed:     openaiSDK.Int(0),
 	}
+
+	if conversation.ResponseFormat != nil {
+		params.ResponseFormat = buildResponseFormat(*conversation.ResponseFormat)
+	}
+
+	if conversation.Audio != nil {
+		params.Modalities = []string{"text", "audio"}
+		params.Audio = openaiSDK.ChatCompletionAudioParam{
+			Voice:  openaiSDK.ChatCompletionAudioParamVoice(orDefault(conversation.Audio.Voice, defaultAudioVoice)),
+			Format: openaiSDK.ChatCompletionAudioParamFormat(orDefault(conversation.Audio.Format, defaultAudioFormat)),
+		}
+	}
+
+	return params
+}
+
+const (
+	defaultAudioVoice  = "alloy"
+	defaultAudioFormat = "wav"
+	// streamedAudioFormat is the only format audio output can be streamed in
+	streamedAudioFormat = "pcm16"
+)
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func buildResponseFormat(format types.ResponseFormat) openaiSDK.ChatCompletionNewParamsResponseFormatUnion {
+	switch format.Type {
+	case types.ResponseFormatJSONObject:
+		return openaiSDK.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &shared.ResponseFormatJSONObjectParam{},
+		}
+
+	case types.ResponseFormatJSONSchema:
+		schema := shared.ResponseFormatJSONSchemaJSONSchemaParam{
+			Name:   format.Name,
+			Schema: format.Schema,
+			Strict: openaiSDK.Bool(format.Strict),
+		}
+		if format.Description != "" {
+			schema.Description = openaiSDK.String(format.Description)
+		}
+		return openaiSDK.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{JSONSchema: schema},
+		}
+
+	default:
+		return openaiSDK.ChatCompletionNewParamsResponseFormatUnion{
+			OfText: &shared.ResponseFormatTextParam{},
+		}
+	}
 }
 
 func buildMessages(conversation types.Context) []openaiSDK.ChatCompletionMessageParamUnion {
@@ -273,7 +735,22 @@ func buildMessages(conversation types.Context) []openaiSDK.ChatCompletionMessage
 		openaiMessages = append(openaiMessages, openaiSDK.SystemMessage(conversation.SystemPrompt))
 	}
 
+	// Tool messages only accept text, so images and files returned by tools
+	// are moved into a user message placed after the run of tool results.
+	pendingAttachments := []openaiSDK.ChatCompletionContentPartUnionParam{}
+	flushAttachments := func() {
+		if len(pendingAttachments) == 0 {
+			return
+		}
+		openaiMessages = append(openaiMessages, openaiSDK.UserMessage(pendingAttachments))
+		pendingAttachments = []openaiSDK.ChatCompletionContentPartUnionParam{}
+	}
+
 	for _, message := range conversation.Messages {
+		if _, ok := message.(types.ToolMessage); !ok {
+			flushAttachments()
+		}
+
 		switch msg := message.(type) {
 		case types.UserMessage:
 			openaiMessages = append(openaiMessages, openaiSDK.UserMessage(buildUserContent(msg.Contents)))
@@ -282,9 +759,22 @@ func buildMessages(conversation types.Context) []openaiSDK.ChatCompletionMessage
 			openaiMessages = append(openaiMessages, buildAssistantMessage(msg))
 
 		case types.ToolMessage:
-			openaiMessages = append(openaiMessages, openaiSDK.ToolMessage(buildToolContent(msg.Contents), msg.ToolCallId))
+			text, attachments := buildToolContent(msg.Contents)
+			if len(attachments) > 0 {
+				pendingAttachments = append(pendingAttachments, openaiSDK.ChatCompletionContentPartUnionParam{
+					OfText: &openaiSDK.ChatCompletionContentPartTextParam{
+						Text: fmt.Sprintf("Attachments returned by tool %s (call %s):", msg.ToolName, msg.ToolCallId),
+					},
+				})
+				pendingAttachments = append(pendingAttachments, attachments...)
+				text = append(text, openaiSDK.ChatCompletionContentPartTextParam{
+					Text: fmt.Sprintf("[%d attachment(s) in the following user message]", len(attachments)),
+				})
+			}
+			openaiMessages = append(openaiMessages, openaiSDK.ToolMessage(text, msg.ToolCallId))
 		}
 	}
+	flushAttachments()
 
 	return openaiMessages
 }
@@ -292,6 +782,7 @@ func buildMessages(conversation types.Context) []openaiSDK.ChatCompletionMessage
 func buildAssistantMessage(msg types.AssistantMessage) openaiSDK.ChatCompletionMessageParamUnion {
 	toolCalls := []openaiSDK.ChatCompletionMessageToolCallUnionParam{}
 	textParts := []openaiSDK.ChatCompletionAssistantMessageParamContentArrayOfContentPartUnion{}
+	var audio *openaiSDK.ChatCompletionAssistantMessageParamAudio
 
 	for _, content := range msg.Contents {
 		switch c := content.(type) {
@@ -300,12 +791,24 @@ func buildAssistantMessage(msg types.AssistantMessage) openaiSDK.ChatCompletionM
 				OfText: &openaiSDK.ChatCompletionContentPartTextParam{Text: c.Text},
 			})
 
+		case types.AudioContent:
+			// Generated audio is referenced by ID while the provider retains it,
+			// otherwise its transcript stands in for it
+			if c.ID != "" && (c.ExpiresAt.IsZero() || time.Now().Before(c.ExpiresAt)) {
+				audio = &openaiSDK.ChatCompletionAssistantMessageParamAudio{ID: c.ID}
+			} else if c.Transcript != "" {
+				textParts = append(textParts, openaiSDK.ChatCompletionAssistantMessageParamContentArrayOfContentPartUnion{
+					OfText: &openaiSDK.ChatCompletionContentPartTextParam{Text: c.Transcript},
+				})
+			}
+
 		case types.ToolCall:
 			toolCalls = append(toolCalls, openaiSDK.ChatCompletionMessageToolCallUnionParam{
 				OfFunction: &openaiSDK.ChatCompletionMessageFunctionToolCallParam{
 					ID: c.ID,
 					Function: openaiSDK.ChatCompletionMessageFunctionToolCallFunctionParam{
-						Name: c.Name,
+						Name:      c.Name,
+						Arguments: toolCallArguments(c),
 					},
 				},
 			})
@@ -321,12 +824,32 @@ func buildAssistantMessage(msg types.AssistantMessage) openaiSDK.ChatCompletionM
 	if len(toolCalls) > 0 {
 		assistantMsg.ToolCalls = toolCalls
 	}
+	if audio != nil {
+		assistantMsg.Audio = *audio
+	}
 
 	return openaiSDK.ChatCompletionMessageParamUnion{
 		OfAssistant: &assistantMsg,
 	}
 }
 
+// toolCallArguments returns the JSON arguments to replay for a previous tool
+// call, preferring the model's original string when it is still valid.
+func toolCallArguments(call types.ToolCall) string {
+	if call.RawArguments != "" && json.Valid([]byte(call.RawArguments)) {
+		return call.RawArguments
+	}
+	if len(call.Arguments) == 0 {
+		return "{}"
+	}
+
+	args, err := json.Marshal(call.Arguments)
+	if err != nil {
+		return "{}"
+	}
+	return string(args)
+}
+
 func buildUserContent(contents []types.Content) []openaiSDK.ChatCompletionContentPartUnionParam {
 	parts := []openaiSDK.ChatCompletionContentPartUnionParam{}
 
@@ -338,27 +861,98 @@ func buildUserContent(contents []types.Content) []openaiSDK.ChatCompletionConten
 			})
 
 		case types.ImageContent:
-			parts = append(parts, openaiSDK.ChatCompletionContentPartUnionParam{
-				OfImageURL: &openaiSDK.ChatCompletionContentPartImageParam{
-					ImageURL: openaiSDK.ChatCompletionContentPartImageImageURLParam{URL: c.Data},
-				},
-			})
+			parts = append(parts, buildImagePart(c))
+
+		case types.DocumentContent:
+			parts = append(parts, buildDocumentPart(c))
+
+		case types.AudioContent:
+			parts = append(parts, buildAudioPart(c))
 		}
 	}
 
 	return parts
 }
 
-func buildToolContent(contents []types.Content) []openaiSDK.ChatCompletionContentPartTextParam {
+func buildAudioPart(audio types.AudioContent) openaiSDK.ChatCompletionContentPartUnionParam {
+	return openaiSDK.ChatCompletionContentPartUnionParam{
+		OfInputAudio: &openaiSDK.ChatCompletionContentPartInputAudioParam{
+			InputAudio: openaiSDK.ChatCompletionContentPartInputAudioInputAudioParam{
+				Data:   audio.Data,
+				Format: orDefault(audio.Format, defaultAudioFormat),
+			},
+		},
+	}
+}
+
+// buildDocumentPart sends plain text documents inline and everything else as a
+// native file input
+func buildDocumentPart(document types.DocumentContent) openaiSDK.ChatCompletionContentPartUnionParam {
+	if text, err := document.Text(); err == nil {
+		return openaiSDK.ChatCompletionContentPartUnionParam{
+			OfText: &openaiSDK.ChatCompletionContentPartTextParam{
+				Text: fmt.Sprintf("<document filename=%q>\n%s\n</document>", document.Filename, text),
+			},
+		}
+	}
+
+	file := openaiSDK.ChatCompletionContentPartFileFileParam{
+		FileData: openaiSDK.String("data:" + document.MimeType + ";base64," + document.Data),
+	}
+	if document.Filename != "" {
+		file.Filename = openaiSDK.String(document.Filename)
+	}
+	return openaiSDK.ChatCompletionContentPartUnionParam{
+		OfFile: &openaiSDK.ChatCompletionContentPartFileParam{File: file},
+	}
+}
+
+func buildImagePart(image types.ImageContent) openaiSDK.ChatCompletionContentPartUnionParam {
+	return openaiSDK.ChatCompletionContentPartUnionParam{
+		OfImageURL: &openaiSDK.ChatCompletionContentPartImageParam{
+			ImageURL: openaiSDK.ChatCompletionContentPartImageImageURLParam{
+				URL:    image.DataURL(),
+				Detail: string(image.Detail),
+			},
+		},
+	}
+}
+
+// buildToolContent splits tool result contents into text parts, with JSON
+// payloads and text documents serialized, and image or file parts that must be
+// sent separately.
+func buildToolContent(contents []types.Content) ([]openaiSDK.ChatCompletionContentPartTextParam, []openaiSDK.ChatCompletionContentPartUnionParam) {
 	parts := []openaiSDK.ChatCompletionContentPartTextParam{}
+	attachments := []openaiSDK.ChatCompletionContentPartUnionParam{}
 
 	for _, content := range contents {
-		if c, ok := content.(types.TextContent); ok {
+		switch c := content.(type) {
+		case types.TextContent:
 			parts = append(parts, openaiSDK.ChatCompletionContentPartTextParam{Text: c.Text})
+
+		case types.JSONContent:
+			data, err := json.Marshal(c.Value)
+			if err != nil {
+				data = []byte(fmt.Sprintf("%v", c.Value))
+			}
+			parts = append(parts, openaiSDK.ChatCompletionContentPartTextParam{Text: string(data)})
+
+		case types.ImageContent:
+			attachments = append(attachments, buildImagePart(c))
+
+		case types.AudioContent:
+			attachments = append(attachments, buildAudioPart(c))
+
+		case types.DocumentContent:
+			if part := buildDocumentPart(c); part.OfText != nil {
+				parts = append(parts, *part.OfText)
+			} else {
+				attachments = append(attachments, part)
+			}
 		}
 	}
 
-	return parts
+	return parts, attachments
 }
 
 func buildTools(tools []types.Tool) []openaiSDK.ChatCompletionToolUnionParam {
@@ -379,6 +973,26 @@ func buildTools(tools []types.Tool) []openaiSDK.ChatCompletionToolUnionParam {
 	return openaiTools
 }
 
+// updateStopReason records the finish reason of a chunk, with the filtered
+// categories when the content filter stopped the response
+func updateStopReason(output *types.AssistantMessage, chunk openaiSDK.ChatCompletionChunk) {
+	if len(chunk.Choices) == 0 || chunk.Choices[0].FinishReason == "" {
+		return
+	}
+	output.StopReason = stopReasonFromOpenAI(string(chunk.Choices[0].FinishReason))
+	if chunk.Choices[0].FinishReason == "content_filter" {
+		output.ErrorMessage = contentFilterMessage(chunk.Choices[0].RawJSON())
+	}
+}
+
+func usageFromOpenAI(usage openaiSDK.CompletionUsage) types.Usage {
+	return types.Usage{
+		InputTokens:  int(usage.PromptTokens),
+		OutputTokens: int(usage.CompletionTokens),
+		TotalTokens:  int(usage.TotalTokens),
+	}
+}
+
 func stopReasonFromOpenAI(reason string) types.StopReason {
 	switch reason {
 	case "stop":
@@ -0,0 +1,16 @@
+package openai_test
+
+import (
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/providertest"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func TestConformance(t *testing.T) {
+	providertest.RunConformance(t, func(t *testing.T, server *providertest.MockServer) provider.Provider {
+		return openai.New(openai.Config{URL: server.URL(), APIKey: "test"}, "mock", types.ProviderOpenAI)
+	})
+}
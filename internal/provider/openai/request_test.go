@@ -0,0 +1,152 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// checkGolden compares the request body built for conversation with testdata/name.json
+func checkGolden(t *testing.T, name string, conversation types.Context) {
+	t.Helper()
+
+	raw, err := json.Marshal(buildParams("gpt-4o", conversation))
+	if err != nil {
+		t.Fatalf("marshal params: %v", err)
+	}
+	var got bytes.Buffer
+	if err := json.Indent(&got, raw, "", "  "); err != nil {
+		t.Fatalf("indent params: %v", err)
+	}
+	got.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".json")
+	if *update {
+		if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("request body differs from %s:\n%s", path, got.String())
+	}
+}
+
+// toolHistory is a turn in which the assistant made calls and received results
+func toolHistory(calls ...types.ToolCall) types.Context {
+	messages := []types.Message{
+		types.UserMessage{Contents: []types.Content{types.TextContent{Text: "What is the weather in Paris and Berlin?"}}},
+		types.AssistantMessage{Contents: append([]types.Content{types.TextContent{Text: "Checking."}}, toContents(calls)...)},
+	}
+	for _, call := range calls {
+		messages = append(messages, types.ToolMessage{
+			ToolCallId: call.ID,
+			ToolName:   call.Name,
+			Contents:   []types.Content{types.TextContent{Text: "18C, cloudy"}},
+		})
+	}
+	return types.Context{Messages: messages}
+}
+
+func toContents(calls []types.ToolCall) []types.Content {
+	contents := make([]types.Content, 0, len(calls))
+	for _, call := range calls {
+		contents = append(contents, call)
+	}
+	return contents
+}
+
+func TestAssistantToolCallRawArguments(t *testing.T) {
+	// RawArguments is replayed byte for byte, including key order and spacing
+	checkGolden(t, "tool_call_raw_arguments", toolHistory(types.ToolCall{
+		ID:           "call_1",
+		Name:         "get_weather",
+		Arguments:    map[string]any{"city": "Paris", "unit": "celsius"},
+		RawArguments: `{"unit": "celsius", "city": "Paris"}`,
+	}))
+}
+
+func TestAssistantToolCallInvalidRawArguments(t *testing.T) {
+	// Truncated raw JSON falls back to the parsed Arguments
+	checkGolden(t, "tool_call_invalid_raw_arguments", toolHistory(types.ToolCall{
+		ID:           "call_1",
+		Name:         "get_weather",
+		Arguments:    map[string]any{"city": "Paris"},
+		RawArguments: `{"city": "Par`,
+	}))
+}
+
+func TestAssistantToolCallNilArguments(t *testing.T) {
+	checkGolden(t, "tool_call_nil_arguments", toolHistory(types.ToolCall{
+		ID:   "call_1",
+		Name: "get_time",
+	}))
+}
+
+func TestAssistantParallelToolCalls(t *testing.T) {
+	checkGolden(t, "tool_call_parallel", toolHistory(
+		types.ToolCall{
+			ID:           "call_1",
+			Name:         "get_weather",
+			Arguments:    map[string]any{"city": "Paris"},
+			RawArguments: `{"city":"Paris"}`,
+		},
+		types.ToolCall{
+			ID:        "call_2",
+			Name:      "get_weather",
+			Arguments: map[string]any{"city": "Berlin"},
+		},
+	))
+}
+
+func TestToolResultImages(t *testing.T) {
+	// Images returned by tools follow the whole run of tool results as one user message
+	conversation := toolHistory(
+		types.ToolCall{ID: "call_1", Name: "get_weather", Arguments: map[string]any{"city": "Paris"}},
+		types.ToolCall{ID: "call_2", Name: "get_weather", Arguments: map[string]any{"city": "Berlin"}},
+	)
+	conversation.Messages[2] = types.ToolMessage{
+		ToolCallId: "call_1",
+		ToolName:   "get_weather",
+		Contents: []types.Content{
+			types.TextContent{Text: "18C, cloudy"},
+			types.ImageFromURL("https://example.com/paris-radar.png"),
+		},
+	}
+	conversation.Messages = append(conversation.Messages,
+		types.UserMessage{Contents: []types.Content{types.TextContent{Text: "Which city is warmer?"}}},
+	)
+	checkGolden(t, "tool_result_images", conversation)
+}
+
+func TestToolCallArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		call types.ToolCall
+		want string
+	}{
+		{"raw", types.ToolCall{RawArguments: `{"b":1, "a":2}`, Arguments: map[string]any{"a": 2}}, `{"b":1, "a":2}`},
+		{"invalid raw", types.ToolCall{RawArguments: `{"a":`, Arguments: map[string]any{"a": 2}}, `{"a":2}`},
+		{"arguments only", types.ToolCall{Arguments: map[string]any{"b": "x", "a": true}}, `{"a":true,"b":"x"}`},
+		{"nil", types.ToolCall{}, `{}`},
+		{"empty", types.ToolCall{Arguments: map[string]any{}}, `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolCallArguments(tt.call); got != tt.want {
+				t.Errorf("toolCallArguments() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
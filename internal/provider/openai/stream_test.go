@@ -0,0 +1,108 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// sseServer streams each delta as a chat completion chunk and records the request body
+func sseServer(t *testing.T, deltas ...string) (*httptest.Server, *[]byte) {
+	t.Helper()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, delta := range deltas {
+			finish := "null"
+			if i == len(deltas)-1 {
+				finish = `"stop"`
+			}
+			fmt.Fprintf(w, "data: {\"id\":\"chatcmpl-1\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"gpt-4o-audio-preview\",\"choices\":[{\"index\":0,\"delta\":%s,\"finish_reason\":%s}]}\n\n", delta, finish)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+// drain collects the event types of a stream and its result
+func drain(stream types.AssistantMessageEventStream) ([]string, types.AssistantMessage, error) {
+	var events []string
+	for event := range stream.Events {
+		events = append(events, fmt.Sprintf("%T", event))
+	}
+	return events, <-stream.Result, <-stream.Err
+}
+
+func TestStreamTextThenAudio(t *testing.T) {
+	server, body := sseServer(t,
+		`{"role":"assistant","content":"Here "}`,
+		`{"content":"it is."}`,
+		`{"audio":{"id":"audio_1","transcript":"Hello","data":"AAAA"}}`,
+		`{"audio":{"data":"BBBB","expires_at":1700000000}}`,
+		`{}`,
+	)
+	p := New(Config{URL: server.URL, APIKey: "test"}, "gpt-4o-audio-preview", types.ProviderOpenAI)
+
+	conversation := hello
+	conversation.Audio = &types.AudioOutput{Voice: "alloy"}
+	events, message, err := drain(p.Stream(context.Background(), conversation))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"types.EventStart",
+		"types.EventTextStart", "types.EventTextDelta", "types.EventTextDelta", "types.EventTextEnd",
+		"types.EventAudioStart", "types.EventAudioDelta", "types.EventAudioDelta", "types.EventAudioEnd",
+		"types.EventDone",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+
+	wantContents := []types.Content{
+		types.TextContent{Text: "Here it is."},
+		types.AudioContent{Format: "pcm16", Data: "AAAABBBB", Transcript: "Hello", ID: "audio_1", ExpiresAt: time.Unix(1700000000, 0)},
+	}
+	if !reflect.DeepEqual(message.Contents, wantContents) {
+		t.Errorf("contents = %#v, want %#v", message.Contents, wantContents)
+	}
+
+	var request struct {
+		Audio struct {
+			Format string `json:"format"`
+		} `json:"audio"`
+	}
+	if err := json.Unmarshal(*body, &request); err != nil {
+		t.Fatal(err)
+	}
+	if request.Audio.Format != "pcm16" {
+		t.Errorf("requested audio format %q, want pcm16", request.Audio.Format)
+	}
+}
+
+func TestStreamRejectsUnstreamableAudioFormat(t *testing.T) {
+	server, body := sseServer(t, `{"content":"unused"}`)
+	p := New(Config{URL: server.URL, APIKey: "test"}, "gpt-4o-audio-preview", types.ProviderOpenAI)
+
+	conversation := hello
+	conversation.Audio = &types.AudioOutput{Format: "wav"}
+	_, _, err := drain(p.Stream(context.Background(), conversation))
+	if !errors.Is(err, types.ErrNotSupported) {
+		t.Errorf("error = %v, want ErrNotSupported", err)
+	}
+	if *body != nil {
+		t.Error("request sent for an unsupported format")
+	}
+}
@@ -0,0 +1,71 @@
+// Package requestkey hashes the parts of a request that influence generation
+package requestkey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+type normalizedMessage struct {
+	Role       string            `json:"role"`
+	Contents   []json.RawMessage `json:"contents"`
+	ToolCallID string            `json:"toolCallId,omitempty"`
+	ToolName   string            `json:"toolName,omitempty"`
+	IsError    bool              `json:"isError,omitempty"`
+}
+
+type normalizedRequest struct {
+	Provider       types.ModelProvider   `json:"provider"`
+	Model          string                `json:"model"`
+	SystemPrompt   string                `json:"systemPrompt"`
+	Messages       []normalizedMessage   `json:"messages"`
+	Tools          []types.Tool          `json:"tools"`
+	Metadata       map[string]any        `json:"metadata"`
+	ResponseFormat *types.ResponseFormat `json:"responseFormat,omitempty"`
+	Audio          *types.AudioOutput    `json:"audio,omitempty"`
+}
+
+// Key returns a hex SHA-256 of a request. Timestamps and other fields that do
+// not influence generation are excluded, so re-sent prompts share a key.
+func Key(providerType types.ModelProvider, modelID string, conversation types.Context) (string, error) {
+	request := normalizedRequest{
+		Provider:       providerType,
+		Model:          modelID,
+		SystemPrompt:   conversation.SystemPrompt,
+		Messages:       make([]normalizedMessage, 0, len(conversation.Messages)),
+		Tools:          conversation.Tools,
+		Metadata:       conversation.Metadata,
+		ResponseFormat: conversation.ResponseFormat,
+		Audio:          conversation.Audio,
+	}
+
+	for _, message := range conversation.Messages {
+		normalized := normalizedMessage{
+			Role:     message.Role(),
+			Contents: make([]json.RawMessage, 0, len(message.Content())),
+		}
+		for _, c := range message.Content() {
+			data, err := types.MarshalContent(c)
+			if err != nil {
+				return "", err
+			}
+			normalized.Contents = append(normalized.Contents, data)
+		}
+		if tm, ok := message.(types.ToolMessage); ok {
+			normalized.ToolCallID = tm.ToolCallId
+			normalized.ToolName = tm.ToolName
+			normalized.IsError = tm.IsError
+		}
+		request.Messages = append(request.Messages, normalized)
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clientCertificate writes a self-signed client certificate and its key,
+// returning their paths and the certificate
+func clientCertificate(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "go-pi-ai client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "client.pem", "CERTIFICATE", der), writePEM(t, "client-key.pem", "EC PRIVATE KEY", keyDER), cert
+}
+
+func serverCA(t *testing.T, server *httptest.Server) string {
+	return writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+}
+
+func get(t *testing.T, opts Options, url string) error {
+	t.Helper()
+	client, err := NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestNewClientTrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	if err := get(t, Options{}, server.URL); err == nil {
+		t.Fatal("request succeeded without trusting the server certificate")
+	}
+	if err := get(t, Options{CAFile: serverCA(t, server)}, server.URL); err != nil {
+		t.Errorf("request with CA bundle: %v", err)
+	}
+}
+
+func TestNewClientMutualTLS(t *testing.T) {
+	certFile, keyFile, cert := clientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := serverCA(t, server)
+
+	if err := get(t, Options{CAFile: caFile}, server.URL); err == nil {
+		t.Fatal("request succeeded without a client certificate")
+	}
+	if err := get(t, Options{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}, server.URL); err != nil {
+		t.Errorf("request with client certificate: %v", err)
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	certFile, keyFile, _ := clientCertificate(t)
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"missing CA bundle", Options{CAFile: missing}},
+		{"CA bundle without certificates", Options{CAFile: empty}},
+		{"missing client certificate", Options{CertFile: missing, KeyFile: keyFile}},
+		{"missing client key", Options{CertFile: certFile, KeyFile: missing}},
+		{"certificate without key", Options{CertFile: certFile}},
+		{"invalid proxy URL", Options{ProxyURL: "http://proxy:port"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewClient(tt.opts); err == nil {
+			t.Errorf("%s: NewClient succeeded", tt.name)
+		}
+	}
+}
+
+func TestNewClientProxy(t *testing.T) {
+	client, err := NewClient(Options{ProxyURL: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openai.com/v1/models", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("proxy = %v, %v", proxy, err)
+	}
+}
@@ -0,0 +1,79 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
+)
+
+const secret = "azure-key-123"
+
+// capture returns a logger redacting secret and account numbers, and the buffer it writes to
+func capture() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	redactor := logging.NewRedactor(logging.DefaultPatterns()...)
+	redactor.AddSecret(secret)
+	redactor.AddPattern(regexp.MustCompile(`ACCT-\d+`))
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(logging.NewHandler(handler, redactor)), &buf
+}
+
+type payload struct {
+	Account string `json:"account"`
+	APIKey  string `json:"apiKey"`
+}
+
+func TestHandlerRedacts(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(*slog.Logger)
+	}{
+		{"message", func(l *slog.Logger) { l.Info("using " + secret) }},
+		{"string attr", func(l *slog.Logger) { l.Info("request", "account", "ACCT-42") }},
+		{"error attr", func(l *slog.Logger) { l.Error("failed", "error", errors.New("invalid key "+secret)) }},
+		{"struct attr", func(l *slog.Logger) { l.Info("payload", "body", payload{Account: "ACCT-42", APIKey: secret}) }},
+		{"group attr", func(l *slog.Logger) { l.Info("request", slog.Group("auth", "key", secret)) }},
+		{"WithAttrs", func(l *slog.Logger) { l.With("key", secret).Info("request") }},
+		{"WithGroup", func(l *slog.Logger) { l.WithGroup("request").With("account", "ACCT-42").Info("sent", "key", secret) }},
+		{"JSON request body", func(l *slog.Logger) {
+			l.Debug("sending request", "payload", `{"messages":[{"role":"user","content":"close ACCT-42"}],"api_key":"`+secret+`"}`)
+		}},
+	}
+	for _, tt := range tests {
+		logger, buf := capture()
+		tt.log(logger)
+
+		out := buf.String()
+		if strings.Contains(out, secret) || strings.Contains(out, "ACCT-42") {
+			t.Errorf("%s: secret logged: %s", tt.name, out)
+		}
+		if !strings.Contains(out, logging.Placeholder) {
+			t.Errorf("%s: no placeholder in %s", tt.name, out)
+		}
+	}
+}
+
+func TestHandlerKeepsOtherValues(t *testing.T) {
+	logger, buf := capture()
+	logger.Info("done", "tokens", 42, "model", "gpt-4o")
+
+	if out := buf.String(); !strings.Contains(out, `"tokens":42`) || !strings.Contains(out, `"model":"gpt-4o"`) {
+		t.Errorf("log = %s, want values unchanged", out)
+	}
+}
+
+func TestOrDiscard(t *testing.T) {
+	if logging.OrDiscard(nil).Enabled(context.Background(), slog.LevelError) {
+		t.Error("discard logger is enabled")
+	}
+	logger, _ := capture()
+	if logging.OrDiscard(logger) != logger {
+		t.Error("OrDiscard replaced a configured logger")
+	}
+}
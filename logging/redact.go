@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces every redacted value
+const Placeholder = "[REDACTED]"
+
+var (
+	// BearerTokenPattern matches Authorization bearer tokens
+	BearerTokenPattern = regexp.MustCompile(`(?i)bearer\s+[a-z0-9._~+/=-]+`)
+	// APIKeyPattern matches common provider API key formats (sk-..., nvapi-...)
+	APIKeyPattern = regexp.MustCompile(`\b(?:sk|nvapi)-[A-Za-z0-9_-]{8,}`)
+	// EmailPattern matches email addresses
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// DefaultPatterns returns the patterns redacted when no custom patterns are configured
+func DefaultPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{BearerTokenPattern, APIKeyPattern}
+}
+
+// Redactor removes secrets and PII from strings before they are logged
+type Redactor struct {
+	secrets  []string
+	refs     map[string]int
+	patterns []*regexp.Regexp
+	mu       sync.RWMutex
+}
+
+// NewRedactor creates a redactor for the given patterns
+func NewRedactor(patterns ...*regexp.Regexp) *Redactor {
+	return &Redactor{
+		refs:     make(map[string]int),
+		patterns: patterns,
+	}
+}
+
+// AddSecret registers a literal value, such as an API key, to be redacted.
+// Secrets are counted, so a value added twice stays redacted until it has
+// been removed twice.
+func (r *Redactor) AddSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refs[secret]++
+	if r.refs[secret] == 1 {
+		r.secrets = append(r.secrets, secret)
+	}
+}
+
+// RemoveSecret releases a value registered with AddSecret, such as a rotated
+// API key that is no longer in use
+func (r *Redactor) RemoveSecret(secret string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refs[secret] == 0 {
+		return
+	}
+	r.refs[secret]--
+	if r.refs[secret] > 0 {
+		return
+	}
+	delete(r.refs, secret)
+	for i, s := range r.secrets {
+		if s == secret {
+			r.secrets = append(r.secrets[:i], r.secrets[i+1:]...)
+			break
+		}
+	}
+}
+
+// AddPattern registers an additional pattern to be redacted
+func (r *Redactor) AddPattern(pattern *regexp.Regexp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Redact returns s with all known secrets and pattern matches replaced
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Placeholder)
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, Placeholder)
+	}
+	return s
+}
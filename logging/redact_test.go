@@ -0,0 +1,66 @@
+package logging_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
+)
+
+func TestRedact(t *testing.T) {
+	redactor := logging.NewRedactor(logging.DefaultPatterns()...)
+	redactor.AddSecret("azure-key-123")
+	redactor.AddPattern(logging.EmailPattern)
+	redactor.AddPattern(regexp.MustCompile(`ACCT-\d+`))
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"secret", "key azure-key-123 used", "key [REDACTED] used"},
+		{"bearer token", "Authorization: Bearer abc.def-123", "Authorization: [REDACTED]"},
+		{"api key", "sent sk-proj1234567890 to openai", "sent [REDACTED] to openai"},
+		{"email", "from ada@example.com", "from [REDACTED]"},
+		{"custom pattern", "account ACCT-42 closed", "account [REDACTED] closed"},
+		{"nothing to redact", "hello", "hello"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := redactor.Redact(tt.in); got != tt.want {
+			t.Errorf("%s: Redact(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactIgnoresEmptySecret(t *testing.T) {
+	redactor := logging.NewRedactor()
+	redactor.AddSecret("")
+	if got := redactor.Redact("hello"); got != "hello" {
+		t.Errorf("Redact = %q, an empty secret matched", got)
+	}
+}
+
+func TestRedactNilRedactor(t *testing.T) {
+	var redactor *logging.Redactor
+	if got := redactor.Redact("sk-proj1234567890"); got != "sk-proj1234567890" {
+		t.Errorf("Redact = %q, want the input unchanged", got)
+	}
+}
+
+func TestRemoveSecret(t *testing.T) {
+	redactor := logging.NewRedactor()
+	redactor.AddSecret("old-key")
+	redactor.AddSecret("old-key")
+	redactor.AddSecret("new-key")
+
+	redactor.RemoveSecret("old-key")
+	if got := redactor.Redact("old-key"); got != logging.Placeholder {
+		t.Errorf("Redact = %q, a secret added twice was released by one removal", got)
+	}
+	redactor.RemoveSecret("old-key")
+	if got := redactor.Redact("old-key new-key"); got != "old-key "+logging.Placeholder {
+		t.Errorf("Redact = %q, want only the released secret kept", got)
+	}
+	redactor.RemoveSecret("unknown")
+}
@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Metric names used by InMemory and the Prometheus exporter
+const (
+	MetricRequests           = "gopiai_requests_total"
+	MetricErrors             = "gopiai_errors_total"
+	MetricTokens             = "gopiai_tokens_total"
+	MetricDuration           = "gopiai_request_duration_seconds"
+	MetricTimeToFirstToken   = "gopiai_time_to_first_token_seconds"
+	MetricInterTokenLatency  = "gopiai_inter_token_latency_seconds"
+	MetricOutputTokensPerSec = "gopiai_output_tokens_per_second"
+)
+
+var (
+	latencyBuckets      = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	interTokenBuckets   = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+	tokensPerSecBuckets = []float64{1, 5, 10, 25, 50, 100, 200, 500}
+)
+
+// Series identifies one time series of a metric
+type Series struct {
+	Labels
+	// ErrorClass is set for MetricErrors
+	ErrorClass provider.ErrorClass
+	// TokenType is "input" or "output" for MetricTokens
+	TokenType string
+}
+
+// Histogram is a snapshot of a histogram series
+type Histogram struct {
+	// Buckets holds the upper bounds; Counts[i] counts observations <= Buckets[i]
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *Histogram) observe(v float64) {
+	for i, bound := range h.Buckets {
+		if v <= bound {
+			h.Counts[i]++
+		}
+	}
+	h.Count++
+	h.Sum += v
+}
+
+func (h *Histogram) clone() Histogram {
+	counts := make([]uint64, len(h.Counts))
+	copy(counts, h.Counts)
+	return Histogram{
+		Buckets: h.Buckets,
+		Counts:  counts,
+		Count:   h.Count,
+		Sum:     h.Sum,
+	}
+}
+
+// InMemory is a Recorder that aggregates measurements in memory
+type InMemory struct {
+	counters   map[string]map[Series]float64
+	histograms map[string]map[Series]*Histogram
+	mu         sync.Mutex
+}
+
+func NewInMemory() *InMemory {
+	return &InMemory{
+		counters:   make(map[string]map[Series]float64),
+		histograms: make(map[string]map[Series]*Histogram),
+	}
+}
+
+func (m *InMemory) IncRequests(labels Labels) {
+	m.add(MetricRequests, Series{Labels: labels}, 1)
+}
+
+func (m *InMemory) IncErrors(labels Labels, class provider.ErrorClass) {
+	m.add(MetricErrors, Series{Labels: labels, ErrorClass: class}, 1)
+}
+
+func (m *InMemory) ObserveDuration(labels Labels, d time.Duration) {
+	m.observe(MetricDuration, labels, d.Seconds(), latencyBuckets)
+}
+
+func (m *InMemory) ObserveTimeToFirstToken(labels Labels, d time.Duration) {
+	m.observe(MetricTimeToFirstToken, labels, d.Seconds(), latencyBuckets)
+}
+
+func (m *InMemory) ObserveInterTokenLatency(labels Labels, d time.Duration) {
+	m.observe(MetricInterTokenLatency, labels, d.Seconds(), interTokenBuckets)
+}
+
+func (m *InMemory) ObserveOutputTokensPerSecond(labels Labels, rate float64) {
+	m.observe(MetricOutputTokensPerSec, labels, rate, tokensPerSecBuckets)
+}
+
+func (m *InMemory) AddUsage(labels Labels, usage types.Usage) {
+	m.add(MetricTokens, Series{Labels: labels, TokenType: "input"}, float64(usage.InputTokens))
+	m.add(MetricTokens, Series{Labels: labels, TokenType: "output"}, float64(usage.OutputTokens))
+}
+
+// Counter returns the current value of a counter series
+func (m *InMemory) Counter(name string, series Series) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[name][series]
+}
+
+// Histogram returns a snapshot of a histogram series
+func (m *InMemory) Histogram(name string, labels Labels) (Histogram, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.histograms[name][Series{Labels: labels}]
+	if !ok {
+		return Histogram{}, false
+	}
+	return h.clone(), true
+}
+
+// Reset drops all recorded measurements
+func (m *InMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters = make(map[string]map[Series]float64)
+	m.histograms = make(map[string]map[Series]*Histogram)
+}
+
+func (m *InMemory) add(name string, series Series, v float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.counters[name]; !ok {
+		m.counters[name] = make(map[Series]float64)
+	}
+	m.counters[name][series] += v
+}
+
+func (m *InMemory) observe(name string, labels Labels, v float64, buckets []float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.histograms[name]; !ok {
+		m.histograms[name] = make(map[Series]*Histogram)
+	}
+	series := Series{Labels: labels}
+	h, ok := m.histograms[name][series]
+	if !ok {
+		h = newHistogram(buckets)
+		m.histograms[name][series] = h
+	}
+	h.observe(v)
+}
+
+type counterSample struct {
+	series Series
+	value  float64
+}
+
+type histogramSample struct {
+	series    Series
+	histogram Histogram
+}
+
+// snapshot returns every series sorted for stable output
+func (m *InMemory) snapshot() (map[string][]counterSample, map[string][]histogramSample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := make(map[string][]counterSample, len(m.counters))
+	for name, series := range m.counters {
+		for s, v := range series {
+			counters[name] = append(counters[name], counterSample{series: s, value: v})
+		}
+		sort.Slice(counters[name], func(i, j int) bool {
+			return seriesLess(counters[name][i].series, counters[name][j].series)
+		})
+	}
+
+	histograms := make(map[string][]histogramSample, len(m.histograms))
+	for name, series := range m.histograms {
+		for s, h := range series {
+			histograms[name] = append(histograms[name], histogramSample{series: s, histogram: h.clone()})
+		}
+		sort.Slice(histograms[name], func(i, j int) bool {
+			return seriesLess(histograms[name][i].series, histograms[name][j].series)
+		})
+	}
+
+	return counters, histograms
+}
+
+func seriesLess(a, b Series) bool {
+	if a.Provider != b.Provider {
+		return a.Provider < b.Provider
+	}
+	if a.Model != b.Model {
+		return a.Model < b.Model
+	}
+	if a.ErrorClass != b.ErrorClass {
+		return a.ErrorClass < b.ErrorClass
+	}
+	return a.TokenType < b.TokenType
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Labels identify the provider and model a measurement belongs to
+type Labels struct {
+	Provider types.ModelProvider
+	Model    string
+}
+
+// Recorder receives measurements from instrumented providers
+type Recorder interface {
+	IncRequests(labels Labels)
+	IncErrors(labels Labels, class provider.ErrorClass)
+	ObserveDuration(labels Labels, d time.Duration)
+	ObserveTimeToFirstToken(labels Labels, d time.Duration)
+	ObserveInterTokenLatency(labels Labels, d time.Duration)
+	ObserveOutputTokensPerSecond(labels Labels, rate float64)
+	AddUsage(labels Labels, usage types.Usage)
+}
+
+// Middleware returns a provider middleware that reports every call to recorder
+func Middleware(recorder Recorder) provider.Middleware {
+	return func(next provider.Provider) provider.Provider {
+		return &instrumentedProvider{
+			next:     next,
+			recorder: recorder,
+			labels: Labels{
+				Provider: next.ProviderType(),
+				Model:    next.Model(),
+			},
+		}
+	}
+}
+
+type instrumentedProvider struct {
+	next     provider.Provider
+	recorder Recorder
+	labels   Labels
+}
+
+func (p *instrumentedProvider) Unwrap() provider.Provider {
+	return p.next
+}
+
+func (p *instrumentedProvider) Model() string {
+	return p.next.Model()
+}
+
+func (p *instrumentedProvider) ProviderType() types.ModelProvider {
+	return p.next.ProviderType()
+}
+
+func (p *instrumentedProvider) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	p.recorder.IncRequests(p.labels)
+	started := time.Now()
+
+	message, err := p.next.Complete(ctx, conversation)
+	elapsed := time.Since(started)
+
+	p.recorder.ObserveDuration(p.labels, elapsed)
+	if err != nil {
+		p.recorder.IncErrors(p.labels, provider.ClassifyError(err))
+		return message, err
+	}
+
+	p.recorder.AddUsage(p.labels, message.Usage)
+	p.observeRate(message.Usage, elapsed)
+	return message, nil
+}
+
+func (p *instrumentedProvider) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
+	p.recorder.IncRequests(p.labels)
+	started := time.Now()
+	var firstToken, lastToken time.Time
+
+	return provider.InterceptStream(
+		p.next.Stream(ctx, conversation),
+		func(event types.AssistantMessageEvent) {
+			switch event.(type) {
+			case types.EventTextDelta, types.EventToolcallDelta, types.EventAudioDelta:
+			default:
+				return
+			}
+
+			now := time.Now()
+			if firstToken.IsZero() {
+				firstToken = now
+				p.recorder.ObserveTimeToFirstToken(p.labels, now.Sub(started))
+			} else {
+				p.recorder.ObserveInterTokenLatency(p.labels, now.Sub(lastToken))
+			}
+			lastToken = now
+		},
+		func(message types.AssistantMessage, err error) {
+			p.recorder.ObserveDuration(p.labels, time.Since(started))
+			if err != nil {
+				p.recorder.IncErrors(p.labels, provider.ClassifyError(err))
+				return
+			}
+
+			p.recorder.AddUsage(p.labels, message.Usage)
+			if !firstToken.IsZero() {
+				p.observeRate(message.Usage, time.Since(firstToken))
+			}
+		},
+	)
+}
+
+func (p *instrumentedProvider) observeRate(usage types.Usage, elapsed time.Duration) {
+	if usage.OutputTokens == 0 || elapsed <= 0 {
+		return
+	}
+	p.recorder.ObserveOutputTokensPerSecond(p.labels, float64(usage.OutputTokens)/elapsed.Seconds())
+}
@@ -0,0 +1,107 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/metrics"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/providertest"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+var labels = metrics.Labels{Provider: types.ProviderOpenAI, Model: "gpt-4o"}
+
+func instrumented(responses ...providertest.Response) (provider.Provider, *metrics.InMemory) {
+	recorder := metrics.NewInMemory()
+	fake := providertest.New(labels.Provider, labels.Model, responses...)
+	return metrics.Middleware(recorder)(fake), recorder
+}
+
+func histogramCount(t *testing.T, recorder *metrics.InMemory, name string) uint64 {
+	t.Helper()
+	h, ok := recorder.Histogram(name, labels)
+	if !ok {
+		return 0
+	}
+	return h.Count
+}
+
+func TestMiddlewareComplete(t *testing.T) {
+	usage := types.Usage{InputTokens: 12, OutputTokens: 3}
+	p, recorder := instrumented(providertest.Text("Hi").WithUsage(usage), providertest.Error(context.DeadlineExceeded))
+
+	if _, err := p.Complete(context.Background(), types.Context{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Complete(context.Background(), types.Context{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Complete error = %v", err)
+	}
+
+	counters := []struct {
+		series metrics.Series
+		name   string
+		want   float64
+	}{
+		{metrics.Series{Labels: labels}, metrics.MetricRequests, 2},
+		{metrics.Series{Labels: labels, ErrorClass: provider.ErrorClassTimeout}, metrics.MetricErrors, 1},
+		{metrics.Series{Labels: labels, TokenType: "input"}, metrics.MetricTokens, 12},
+		{metrics.Series{Labels: labels, TokenType: "output"}, metrics.MetricTokens, 3},
+	}
+	for _, c := range counters {
+		if got := recorder.Counter(c.name, c.series); got != c.want {
+			t.Errorf("%s %+v = %v, want %v", c.name, c.series, got, c.want)
+		}
+	}
+	if got := histogramCount(t, recorder, metrics.MetricDuration); got != 2 {
+		t.Errorf("duration observations = %d, want 2", got)
+	}
+	if got := histogramCount(t, recorder, metrics.MetricOutputTokensPerSec); got != 1 {
+		t.Errorf("tokens per second observations = %d, want 1 for the successful call", got)
+	}
+	if got := histogramCount(t, recorder, metrics.MetricTimeToFirstToken); got != 0 {
+		t.Errorf("time to first token observed for Complete")
+	}
+}
+
+func TestMiddlewareStream(t *testing.T) {
+	failure := errors.New("connection reset")
+	p, recorder := instrumented(
+		providertest.Text("Hello there").WithChunkSize(3).WithUsage(types.Usage{InputTokens: 5, OutputTokens: 4}),
+		providertest.Text("Hello there").WithChunkSize(3).FailingAfter(1, failure),
+	)
+
+	for range 2 {
+		stream := p.Stream(context.Background(), types.Context{})
+		for range stream.Events {
+		}
+		<-stream.Result
+		<-stream.Err
+	}
+
+	// Hello there in chunks of three runes is four deltas per stream
+	tests := []struct {
+		name string
+		want uint64
+	}{
+		{metrics.MetricDuration, 2},
+		{metrics.MetricTimeToFirstToken, 2},
+		{metrics.MetricInterTokenLatency, 3},
+		{metrics.MetricOutputTokensPerSec, 1},
+	}
+	for _, tt := range tests {
+		if got := histogramCount(t, recorder, tt.name); got != tt.want {
+			t.Errorf("%s observations = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := recorder.Counter(metrics.MetricRequests, metrics.Series{Labels: labels}); got != 2 {
+		t.Errorf("requests = %v, want 2", got)
+	}
+	if got := recorder.Counter(metrics.MetricTokens, metrics.Series{Labels: labels, TokenType: "output"}); got != 4 {
+		t.Errorf("output tokens = %v, want only the successful stream", got)
+	}
+	if got := recorder.Counter(metrics.MetricErrors, metrics.Series{Labels: labels, ErrorClass: provider.ErrorClassUnknown}); got != 1 {
+		t.Errorf("errors = %v, want 1", got)
+	}
+}
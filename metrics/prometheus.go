@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var metricHelp = map[string]string{
+	MetricRequests:           "Total number of model requests.",
+	MetricErrors:             "Total number of failed model requests by error class.",
+	MetricTokens:             "Total number of tokens consumed by type.",
+	MetricDuration:           "Total model request duration in seconds.",
+	MetricTimeToFirstToken:   "Time from request start to the first streamed token in seconds.",
+	MetricInterTokenLatency:  "Time between consecutive streamed tokens in seconds.",
+	MetricOutputTokensPerSec: "Output tokens generated per second.",
+}
+
+var metricOrder = []string{
+	MetricRequests,
+	MetricErrors,
+	MetricTokens,
+	MetricDuration,
+	MetricTimeToFirstToken,
+	MetricInterTokenLatency,
+	MetricOutputTokensPerSec,
+}
+
+// PrometheusExporter renders an InMemory recorder in the Prometheus text format
+type PrometheusExporter struct {
+	source *InMemory
+}
+
+func NewPrometheusExporter(source *InMemory) *PrometheusExporter {
+	return &PrometheusExporter{
+		source: source,
+	}
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format
+func (e *PrometheusExporter) WriteTo(w io.Writer) (int64, error) {
+	counters, histograms := e.source.snapshot()
+
+	var buf bytes.Buffer
+	for _, name := range metricOrder {
+		if samples, ok := counters[name]; ok {
+			writeHeader(&buf, name, "counter")
+			for _, s := range samples {
+				fmt.Fprintf(&buf, "%s%s %s\n", name, formatLabels(s.series), formatFloat(s.value))
+			}
+		}
+
+		if samples, ok := histograms[name]; ok {
+			writeHeader(&buf, name, "histogram")
+			for _, s := range samples {
+				writeHistogram(&buf, name, s.series, s.histogram)
+			}
+		}
+	}
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ServeHTTP serves the metrics for a Prometheus scrape
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = e.WriteTo(w)
+}
+
+func writeHeader(buf *bytes.Buffer, name, kind string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, metricHelp[name])
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+func writeHistogram(buf *bytes.Buffer, name string, series Series, h Histogram) {
+	for i, bound := range h.Buckets {
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", name, formatLabels(series, "le", formatFloat(bound)), h.Counts[i])
+	}
+	fmt.Fprintf(buf, "%s_bucket%s %d\n", name, formatLabels(series, "le", "+Inf"), h.Count)
+	fmt.Fprintf(buf, "%s_sum%s %s\n", name, formatLabels(series), formatFloat(h.Sum))
+	fmt.Fprintf(buf, "%s_count%s %d\n", name, formatLabels(series), h.Count)
+}
+
+func formatLabels(series Series, extra ...string) string {
+	pairs := []string{
+		"provider", string(series.Provider),
+		"model", series.Model,
+	}
+	if series.ErrorClass != "" {
+		pairs = append(pairs, "class", string(series.ErrorClass))
+	}
+	if series.TokenType != "" {
+		pairs = append(pairs, "type", series.TokenType)
+	}
+	pairs = append(pairs, extra...)
+
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,68 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/metrics"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func TestPrometheusExporter(t *testing.T) {
+	recorder := metrics.NewInMemory()
+	recorder.IncRequests(labels)
+	recorder.IncRequests(labels)
+	recorder.IncErrors(metrics.Labels{Provider: types.ProviderAzure, Model: `prod "east"`}, provider.ErrorClassRateLimit)
+	recorder.ObserveDuration(labels, 300*time.Millisecond)
+	recorder.ObserveDuration(labels, 2*time.Second)
+
+	var buf bytes.Buffer
+	if _, err := metrics.NewPrometheusExporter(recorder).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP gopiai_requests_total Total number of model requests.
+# TYPE gopiai_requests_total counter
+gopiai_requests_total{provider="openai",model="gpt-4o"} 2
+# HELP gopiai_errors_total Total number of failed model requests by error class.
+# TYPE gopiai_errors_total counter
+gopiai_errors_total{provider="azure",model="prod \"east\"",class="rate_limit"} 1
+# HELP gopiai_request_duration_seconds Total model request duration in seconds.
+# TYPE gopiai_request_duration_seconds histogram
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="0.05"} 0
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="0.1"} 0
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="0.25"} 0
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="0.5"} 1
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="1"} 1
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="2.5"} 2
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="5"} 2
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="10"} 2
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="30"} 2
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="60"} 2
+gopiai_request_duration_seconds_bucket{provider="openai",model="gpt-4o",le="+Inf"} 2
+gopiai_request_duration_seconds_sum{provider="openai",model="gpt-4o"} 2.3
+gopiai_request_duration_seconds_count{provider="openai",model="gpt-4o"} 2
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTo =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrometheusServeHTTP(t *testing.T) {
+	recorder := metrics.NewInMemory()
+	recorder.IncRequests(labels)
+
+	response := httptest.NewRecorder()
+	metrics.NewPrometheusExporter(recorder).ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := response.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(response.Body.String(), `gopiai_requests_total{provider="openai",model="gpt-4o"} 1`) {
+		t.Errorf("body = %s", response.Body.String())
+	}
+}
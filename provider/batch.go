@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Batcher is implemented by providers that support an asynchronous batch API
+type Batcher interface {
+	SubmitBatch(ctx context.Context, requests []types.BatchRequest) (types.BatchJob, error)
+	BatchStatus(ctx context.Context, batchID string) (types.BatchJob, error)
+	BatchResults(ctx context.Context, job types.BatchJob) ([]types.BatchResult, error)
+	CancelBatch(ctx context.Context, batchID string) (types.BatchJob, error)
+}
+
+// AsBatcher returns the Batcher behind p, looking through middleware wrappers
+func AsBatcher(p Provider) (Batcher, bool) {
+	for p != nil {
+		if batcher, ok := p.(Batcher); ok {
+			return batcher, true
+		}
+		wrapper, ok := p.(Wrapper)
+		if !ok {
+			return nil, false
+		}
+		p = wrapper.Unwrap()
+	}
+	return nil, false
+}
+
+func (r *Registry) GetBatcher(model types.Model) (Batcher, error) {
+	p, err := r.Get(model.Provider, model.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	batcher, ok := AsBatcher(p)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s does not support batches", types.ErrNotSupported, model.Provider, model.ID)
+	}
+	return batcher, nil
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/catalog"
+	openaiProvider "github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// defaultDiscoveryTimeout bounds each provider's model listing at init
+const defaultDiscoveryTimeout = 10 * time.Second
+
+// WithModelDiscovery registers every model listed by each configured
+// provider's /models endpoint when RegisterFromConfig runs. A timeout of zero
+// uses the default of ten seconds per provider.
+func WithModelDiscovery(timeout time.Duration) RegistryOption {
+	return func(r *Registry) {
+		if timeout <= 0 {
+			timeout = defaultDiscoveryTimeout
+		}
+		r.discover = true
+		r.discoveryTimeout = timeout
+	}
+}
+
+// WithLazyModels makes Get create a provider on first use for model IDs of a
+// configured provider that were not registered up front
+func WithLazyModels() RegistryOption {
+	return func(r *Registry) {
+		r.lazy = true
+	}
+}
+
+// DiscoverModels queries the provider's model listing, registers any models
+// not yet known and returns the listing merged with catalog metadata
+func (r *Registry) DiscoverModels(ctx context.Context, providerType types.ModelProvider) ([]catalog.ModelInfo, error) {
+	r.mu.RLock()
+	clientCfg, ok := r.clientConfigs[providerType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %s", types.ErrProviderNotFound, providerType)
+	}
+
+	ids, err := openaiProvider.ListModels(ctx, clientCfg)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The provider may have been reloaded while the listing was fetched
+	clientCfg, ok = r.clientConfigs[providerType]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %s", types.ErrProviderNotFound, providerType)
+	}
+
+	infos := make([]catalog.ModelInfo, 0, len(ids))
+	for _, modelID := range ids {
+		if _, err := r.get(providerType, modelID); err != nil {
+			r.register(providerType, modelID, r.newProvider(providerType, clientCfg, modelID))
+		}
+
+		info, ok := catalog.Lookup(types.Model{Provider: providerType, ID: modelID})
+		if !ok {
+			info = catalog.ModelInfo{Provider: providerType, ID: modelID, Name: modelID}
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// getOrCreate registers a provider for an unknown model of a configured provider
+func (r *Registry) getOrCreate(providerType types.ModelProvider, modelID string) (Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if provider, err := r.get(providerType, modelID); err == nil {
+		return provider, nil
+	}
+
+	clientCfg, ok := r.clientConfigs[providerType]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %s", types.ErrProviderNotFound, providerType)
+	}
+
+	r.register(providerType, modelID, r.newProvider(providerType, clientCfg, modelID))
+	return r.get(providerType, modelID)
+}
@@ -0,0 +1,147 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// modelServer lists models on /models and answers chat completions with the
+// requested model, recording the models requested
+func modelServer(t *testing.T, listed ...string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/models":
+			data := make([]map[string]any, 0, len(listed))
+			for _, id := range listed {
+				data = append(data, map[string]any{"id": id, "object": "model", "created": 1, "owned_by": "test"})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+		case "/chat/completions":
+			var request struct {
+				Model string `json:"model"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			mu.Lock()
+			requested = append(requested, request.Model)
+			mu.Unlock()
+			fmt.Fprintf(w, `{"id": "chatcmpl-1", "object": "chat.completion", "created": 1, "model": %q,
+				"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`, request.Model)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+func serverConfig(url string) *config.Config {
+	cfg := config.NewConfig()
+	cfg.SetProvider(types.ProviderOpenAI, config.ProviderConfig{BaseURL: url, APIKey: "sk-test", Models: []string{"gpt-4o"}})
+	return cfg
+}
+
+func TestModelDiscovery(t *testing.T) {
+	server, requested := modelServer(t, "gpt-4o", "ft:gpt-4o:acme")
+	registry, err := provider.NewRegistryFromConfig(serverConfig(server.URL), provider.WithModelDiscovery(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := registry.Get(types.ProviderOpenAI, "ft:gpt-4o:acme")
+	if err != nil {
+		t.Fatalf("discovered model not registered: %v", err)
+	}
+	if _, err := p.Complete(context.Background(), types.Context{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := requested(); len(got) != 1 || got[0] != "ft:gpt-4o:acme" {
+		t.Errorf("requested models = %v", got)
+	}
+
+	infos, err := registry.DiscoverModels(context.Background(), types.ProviderOpenAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]string{}
+	for _, info := range infos {
+		names[info.ID] = info.Name
+	}
+	if names["gpt-4o"] != "GPT-4o" || names["ft:gpt-4o:acme"] != "ft:gpt-4o:acme" {
+		t.Errorf("DiscoverModels names = %v, want catalog metadata merged", names)
+	}
+
+	if _, err := registry.DiscoverModels(context.Background(), types.ProviderNvidia); !errors.Is(err, types.ErrProviderNotFound) {
+		t.Errorf("unconfigured provider: DiscoverModels error = %v", err)
+	}
+}
+
+func TestModelDiscoveryFailureKeepsConfiguredModels(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	registry, err := provider.NewRegistryFromConfig(serverConfig(server.URL), provider.WithModelDiscovery(0))
+	if err != nil {
+		t.Fatalf("discovery failure failed registration: %v", err)
+	}
+	if _, err := registry.Get(types.ProviderOpenAI, "gpt-4o"); err != nil {
+		t.Errorf("configured model: %v", err)
+	}
+}
+
+func TestLazyModels(t *testing.T) {
+	server, requested := modelServer(t)
+
+	strict, err := provider.NewRegistryFromConfig(serverConfig(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.Get(types.ProviderOpenAI, "gpt-4.1"); !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("Get without lazy models = %v, want ErrModelNotFound", err)
+	}
+
+	lazy, err := provider.NewRegistryFromConfig(serverConfig(server.URL), provider.WithLazyModels())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := lazy.Get(types.ProviderOpenAI, "gpt-4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := lazy.Get(types.ProviderOpenAI, "gpt-4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("second Get created another provider")
+	}
+	if _, err := first.Complete(context.Background(), types.Context{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := requested(); len(got) != 1 || got[0] != "gpt-4.1" {
+		t.Errorf("requested models = %v, want gpt-4.1", got)
+	}
+
+	if _, err := lazy.Get(types.ProviderNvidia, "llama"); !errors.Is(err, types.ErrProviderNotFound) {
+		t.Errorf("unconfigured provider: Get error = %v, want ErrProviderNotFound", err)
+	}
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Embedder generates vector embeddings for text inputs
+type Embedder interface {
+	Embed(
+		ctx context.Context,
+		inputs []string,
+		opts types.EmbedOptions,
+	) ([][]float32, types.Usage, error)
+}
+
+func Embed(ctx context.Context, model types.Model, inputs []string, opts types.EmbedOptions) ([][]float32, types.Usage, error) {
+	registry, err := GetRegistry()
+	if err != nil {
+		return nil, types.Usage{}, fmt.Errorf("failed to initialize registry: %w", err)
+	}
+	return registry.Embed(ctx, model, inputs, opts)
+}
+
+// Embed generates embeddings with a model registered in this registry
+func (r *Registry) Embed(ctx context.Context, model types.Model, inputs []string, opts types.EmbedOptions) ([][]float32, types.Usage, error) {
+	e, err := r.GetEmbedder(model)
+	if err != nil {
+		return nil, types.Usage{}, err
+	}
+	return e.Embed(ctx, inputs, opts)
+}
+
+func (r *Registry) RegisterEmbedder(model types.Model, embedder Embedder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.registerEmbedder(model, embedder)
+	return nil
+}
+
+func (r *Registry) registerEmbedder(model types.Model, embedder Embedder) {
+	if _, ok := r.embedders[model.Provider]; !ok {
+		r.embedders[model.Provider] = make(map[string]Embedder)
+	}
+	r.embedders[model.Provider][model.ID] = embedder
+}
+
+func (r *Registry) GetEmbedder(model types.Model) (Embedder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	embedders, ok := r.embedders[model.Provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: provider %s", types.ErrProviderNotFound, model.Provider)
+	}
+
+	embedder, ok := embedders[model.ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: embedding model %s", types.ErrModelNotFound, model.ID)
+	}
+
+	return embedder, nil
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	openaiProvider "github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// ErrorClass groups provider errors into coarse categories
+type ErrorClass string
+
+const (
+	ErrorClassCanceled       ErrorClass = "canceled"
+	ErrorClassTimeout        ErrorClass = "timeout"
+	ErrorClassRateLimit      ErrorClass = "rate_limit"
+	ErrorClassAuth           ErrorClass = "auth"
+	ErrorClassInvalidRequest ErrorClass = "invalid_request"
+	ErrorClassServer         ErrorClass = "server"
+	ErrorClassNetwork        ErrorClass = "network"
+	ErrorClassUnknown        ErrorClass = "unknown"
+)
+
+// ClassifyError returns the class of an error returned by a provider
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, types.ErrUnsupportedContent), errors.Is(err, types.ErrContentTooLarge), errors.Is(err, types.ErrContentFiltered):
+		return ErrorClassInvalidRequest
+	case errors.Is(err, types.ErrCredentials):
+		return ErrorClassAuth
+	}
+
+	if status, ok := openaiProvider.StatusCode(err); ok {
+		switch {
+		case status == http.StatusTooManyRequests:
+			return ErrorClassRateLimit
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return ErrorClassAuth
+		case status == http.StatusRequestTimeout:
+			return ErrorClassTimeout
+		case status >= 500:
+			return ErrorClassServer
+		case status >= 400:
+			return ErrorClassInvalidRequest
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassUnknown
+}
@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/requestkey"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// ErrCheckpointMismatch is returned when a checkpoint file belongs to a different run
+var ErrCheckpointMismatch = errors.New("checkpoint does not match requests")
+
+// CompleteResult is the outcome of one conversation passed to CompleteMany
+type CompleteResult struct {
+	Message types.AssistantMessage
+	Err     error
+	// Attempts is the number of calls made, including retries
+	Attempts int
+}
+
+// Progress reports the state of a CompleteMany run after an item finishes
+type Progress struct {
+	Index     int
+	Err       error
+	Done      int
+	Failed    int
+	Total     int
+	Resumed   int
+	Remaining int
+}
+
+// manyOptions holds CompleteMany settings
+type manyOptions struct {
+	concurrency    int
+	requestsPerSec float64
+	retries        int
+	backoff        time.Duration
+	onProgress     func(Progress)
+	checkpointPath string
+}
+
+// ManyOption configures CompleteMany
+type ManyOption func(*manyOptions)
+
+// WithConcurrency limits the number of requests in flight (default 4)
+func WithConcurrency(n int) ManyOption {
+	return func(o *manyOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithRateLimit limits how many requests are started per second
+func WithRateLimit(requestsPerSecond float64) ManyOption {
+	return func(o *manyOptions) {
+		o.requestsPerSec = requestsPerSecond
+	}
+}
+
+// WithRetries retries rate limited, server, network and timeout errors up to
+// n times with exponential backoff starting at backoff
+func WithRetries(n int, backoff time.Duration) ManyOption {
+	return func(o *manyOptions) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// WithProgress is called after every item completes
+func WithProgress(fn func(Progress)) ManyOption {
+	return func(o *manyOptions) {
+		o.onProgress = fn
+	}
+}
+
+// WithCheckpoint persists successful results to path so a rerun of the same
+// requests only sends the items that have not succeeded yet. Items whose
+// conversation changed since the checkpoint was written are sent again.
+func WithCheckpoint(path string) ManyOption {
+	return func(o *manyOptions) {
+		o.checkpointPath = path
+	}
+}
+
+// CompleteMany completes conversations concurrently. Results are in input
+// order and per-item failures are reported in CompleteResult.Err; the
+// returned error is only set when the run itself could not proceed.
+func CompleteMany(ctx context.Context, model types.Model, conversations []types.Context, opts ...ManyOption) ([]CompleteResult, error) {
+	registry, err := GetRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize registry: %w", err)
+	}
+	return registry.CompleteMany(ctx, model, conversations, opts...)
+}
+
+// CompleteMany completes conversations concurrently with models from this registry
+func (r *Registry) CompleteMany(ctx context.Context, model types.Model, conversations []types.Context, opts ...ManyOption) ([]CompleteResult, error) {
+	return completeMany(ctx, r.lookup, model, conversations, opts...)
+}
+
+// completeMany runs the conversations against the provider lookup returns for
+// each of them, so aliases can route items to different models
+func completeMany(
+	ctx context.Context,
+	lookup func(types.Model, types.Context) (Provider, error),
+	model types.Model,
+	conversations []types.Context,
+	opts ...ManyOption,
+) ([]CompleteResult, error) {
+	options := manyOptions{concurrency: 4, backoff: time.Second}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+
+	results := make([]CompleteResult, len(conversations))
+
+	var cp *checkpoint
+	if options.checkpointPath != "" {
+		var err error
+		cp, err = loadCheckpoint(options.checkpointPath, model, conversations)
+		if err != nil {
+			return nil, err
+		}
+		defer cp.close()
+	}
+
+	pending := make([]int, 0, len(conversations))
+	resumed := 0
+	for i := range conversations {
+		if message, ok := cp.result(i); ok {
+			results[i] = CompleteResult{Message: message}
+			resumed++
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	limiter := newRateLimiter(options.requestsPerSec)
+	defer limiter.stop()
+
+	var (
+		mu       sync.Mutex
+		done     = resumed
+		failed   int
+		firstErr error
+	)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(options.concurrency, max(len(pending), 1)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var result CompleteResult
+				if p, err := lookup(model, conversations[i]); err != nil {
+					result.Err = err
+				} else {
+					result = completeWithRetry(ctx, p, conversations[i], limiter, options)
+				}
+
+				mu.Lock()
+				results[i] = result
+				done++
+				if result.Err != nil {
+					failed++
+				} else if err := cp.save(i, result.Message); err != nil && firstErr == nil {
+					firstErr = err
+				}
+				progress := Progress{
+					Index:     i,
+					Err:       result.Err,
+					Done:      done,
+					Failed:    failed,
+					Total:     len(conversations),
+					Resumed:   resumed,
+					Remaining: len(conversations) - done,
+				}
+				if options.onProgress != nil {
+					options.onProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Items never started because the context ended
+	for _, i := range pending {
+		if results[i].Attempts == 0 && results[i].Err == nil {
+			results[i].Err = ctx.Err()
+		}
+	}
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, nil
+}
+
+func completeWithRetry(ctx context.Context, p Provider, conversation types.Context, limiter *rateLimiter, options manyOptions) CompleteResult {
+	result := CompleteResult{}
+	backoff := options.backoff
+
+	for {
+		if err := limiter.wait(ctx); err != nil {
+			result.Err = err
+			return result
+		}
+
+		result.Attempts++
+		result.Message, result.Err = p.Complete(ctx, conversation)
+		if result.Err == nil || result.Attempts > options.retries || ctx.Err() != nil || !retryable(result.Err) {
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func retryable(err error) bool {
+	switch ClassifyError(err) {
+	case ErrorClassRateLimit, ErrorClassServer, ErrorClassNetwork, ErrorClassTimeout:
+		return true
+	}
+	return false
+}
+
+// rateLimiter spaces request starts evenly; a nil limiter never blocks
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / requestsPerSecond))}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.ticker.C:
+		return nil
+	}
+}
+
+func (l *rateLimiter) stop() {
+	if l != nil {
+		l.ticker.Stop()
+	}
+}
+
+// checkpoint stores successful CompleteMany results by input index in a JSON
+// lines file: a header naming the run, then one record per result, appended
+// as items finish. A nil checkpoint stores nothing.
+type checkpoint struct {
+	file *os.File
+	// keys hash each conversation, so results of edited items are not reused
+	keys    []string
+	results map[int]types.AssistantMessage
+}
+
+type checkpointHeader struct {
+	Model types.Model `json:"model"`
+	Total int         `json:"total"`
+}
+
+type checkpointRecord struct {
+	Index   int                    `json:"index"`
+	Key     string                 `json:"key"`
+	Message types.AssistantMessage `json:"message"`
+}
+
+func loadCheckpoint(path string, model types.Model, conversations []types.Context) (*checkpoint, error) {
+	cp := &checkpoint{
+		keys:    make([]string, len(conversations)),
+		results: map[int]types.AssistantMessage{},
+	}
+	for i, conversation := range conversations {
+		key, err := requestkey.Key(model.Provider, model.ID, conversation)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint key: %w", err)
+		}
+		cp.keys[i] = key
+	}
+	header := checkpointHeader{Model: model, Total: len(conversations)}
+
+	raw, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if len(raw) > 0 {
+		lines := bytes.Split(raw, []byte("\n"))
+		var stored checkpointHeader
+		if err := json.Unmarshal(lines[0], &stored); err != nil {
+			return nil, fmt.Errorf("decode checkpoint: %w", err)
+		}
+		if stored != header {
+			return nil, fmt.Errorf("%w: %s was written for %d requests to %s/%s", ErrCheckpointMismatch, path, stored.Total, stored.Model.Provider, stored.Model.ID)
+		}
+		for _, line := range lines[1:] {
+			var record checkpointRecord
+			// Undecodable lines were cut short by a crash
+			if len(line) == 0 || json.Unmarshal(line, &record) != nil {
+				continue
+			}
+			if record.Index >= 0 && record.Index < len(cp.keys) && record.Key == cp.keys[record.Index] {
+				cp.results[record.Index] = record.Message
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("write checkpoint: %w", err)
+	}
+	cp.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("write checkpoint: %w", err)
+	}
+
+	var prefix []byte
+	switch {
+	case len(raw) == 0:
+		prefix, _ = json.Marshal(header)
+		prefix = append(prefix, '\n')
+	case raw[len(raw)-1] != '\n':
+		// Terminate a partial record so the next one starts on its own line
+		prefix = []byte("\n")
+	}
+	if err := cp.append(prefix); err != nil {
+		cp.close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (c *checkpoint) result(index int) (types.AssistantMessage, bool) {
+	if c == nil {
+		return types.AssistantMessage{}, false
+	}
+	message, ok := c.results[index]
+	return message, ok
+}
+
+// save appends a result to the checkpoint file
+func (c *checkpoint) save(index int, message types.AssistantMessage) error {
+	if c == nil {
+		return nil
+	}
+	c.results[index] = message
+
+	line, err := json.Marshal(checkpointRecord{Index: index, Key: c.keys[index], Message: message})
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	return c.append(append(line, '\n'))
+}
+
+// append writes data and flushes it to disk, so finished items survive a crash
+func (c *checkpoint) append(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if _, err := c.file.Write(data); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := c.file.Sync(); err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (c *checkpoint) close() {
+	if c != nil {
+		c.file.Close()
+	}
+}
@@ -0,0 +1,144 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/providertest"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func prompts(texts ...string) []types.Context {
+	conversations := make([]types.Context, 0, len(texts))
+	for _, text := range texts {
+		conversations = append(conversations, types.Context{Messages: []types.Message{
+			types.UserMessage{Timestamp: time.Now(), Contents: []types.Content{types.TextContent{Text: text}}},
+		}})
+	}
+	return conversations
+}
+
+func callTexts(fake *providertest.Fake) []string {
+	var texts []string
+	for _, call := range fake.Calls() {
+		texts = append(texts, call.Messages[0].Content()[0].(types.TextContent).Text)
+	}
+	return texts
+}
+
+func answer(t *testing.T, result provider.CompleteResult) string {
+	t.Helper()
+	if result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	return result.Message.Contents[0].(types.TextContent).Text
+}
+
+func TestCompleteManyResumesFromCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "checkpoint.jsonl")
+	opts := []provider.ManyOption{provider.WithCheckpoint(path), provider.WithConcurrency(1)}
+
+	registry, _ := fakeRegistry(t,
+		providertest.Text("one"),
+		providertest.Error(errors.New("invalid request")),
+		providertest.Text("three"),
+	)
+	results, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2", "3"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[1].Err == nil {
+		t.Fatal("scripted failure succeeded")
+	}
+
+	// Rebuilt conversations carry new timestamps but the same content
+	registry, fake := fakeRegistry(t, providertest.Text("two"))
+	results, err = registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2", "3"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := callTexts(fake); len(calls) != 1 || calls[0] != "2" {
+		t.Errorf("resumed run sent %v, want only the failed item", calls)
+	}
+	for i, want := range []string{"one", "two", "three"} {
+		if got := answer(t, results[i]); got != want {
+			t.Errorf("result %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestCompleteManyResendsEditedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	opts := []provider.ManyOption{provider.WithCheckpoint(path), provider.WithConcurrency(1)}
+
+	registry, _ := fakeRegistry(t, providertest.Text("one"), providertest.Text("two"))
+	if _, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2"), opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, fake := fakeRegistry(t, providertest.Text("edited"))
+	results, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2 (edited)"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := callTexts(fake); len(calls) != 1 || calls[0] != "2 (edited)" {
+		t.Errorf("sent %v, want only the edited item", calls)
+	}
+	if got := answer(t, results[1]); got != "edited" {
+		t.Errorf("result 1 = %q, want the new answer", got)
+	}
+}
+
+func TestCompleteManyIgnoresTruncatedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	opts := []provider.ManyOption{provider.WithCheckpoint(path), provider.WithConcurrency(1)}
+
+	registry, _ := fakeRegistry(t, providertest.Text("one"))
+	if _, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2"), opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash while writing the second record leaves half a line behind
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"index":1,"key":"`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	// The first rerun resends the item, the second finds its record intact
+	for run, wantCalls := range []int{1, 0} {
+		registry, fake := fakeRegistry(t, providertest.Text("two"))
+		results, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2"), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.Calls()) != wantCalls {
+			t.Errorf("run %d sent %v, want %d requests", run, callTexts(fake), wantCalls)
+		}
+		if got := answer(t, results[1]); got != "two" {
+			t.Errorf("run %d: result 1 = %q", run, got)
+		}
+	}
+}
+
+func TestCompleteManyRejectsOtherCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	registry, _ := fakeRegistry(t, providertest.Text("one"))
+	if _, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1"), provider.WithCheckpoint(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := registry.CompleteMany(context.Background(), fakeModel, prompts("1", "2"), provider.WithCheckpoint(path))
+	if !errors.Is(err, provider.ErrCheckpointMismatch) {
+		t.Errorf("error = %v, want ErrCheckpointMismatch", err)
+	}
+}
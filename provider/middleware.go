@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Middleware decorates a Provider with cross-cutting behaviour such as tracing
+type Middleware func(Provider) Provider
+
+// Wrapper is implemented by providers that decorate another provider
+type Wrapper interface {
+	Unwrap() Provider
+}
+
+// Chain applies middlewares to p; the first middleware becomes the outermost layer
+func Chain(p Provider, middlewares ...Middleware) Provider {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		p = middlewares[i](p)
+	}
+	return p
+}
+
+// InterceptStream forwards every event of in to a new stream. onEvent is
+// called before each event is delivered and onFinish once with the final
+// message and error, before they are handed to the consumer.
+func InterceptStream(
+	in types.AssistantMessageEventStream,
+	onEvent func(types.AssistantMessageEvent),
+	onFinish func(types.AssistantMessage, error),
+) types.AssistantMessageEventStream {
+	out := types.NewAssistantMessageEventStream()
+
+	go func() {
+		for event := range in.Events {
+			if onEvent != nil {
+				onEvent(event)
+			}
+			out.Events <- event
+		}
+
+		result := <-in.Result
+		err := <-in.Err
+		if onFinish != nil {
+			onFinish(result, err)
+		}
+
+		out.Result <- result
+		out.Err <- err
+		out.Close()
+	}()
+
+	return out
+}
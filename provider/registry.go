@@ -3,59 +3,81 @@ package provider
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/rahulSailesh-shah/go-pi-ai/config"
 	openaiProvider "github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/transport"
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
 	"github.com/rahulSailesh-shah/go-pi-ai/types"
 )
 
+// Stream streams a response from model, which may be an Alias, using the default registry
 func Stream(ctx context.Context, model types.Model, conversation types.Context) (types.AssistantMessageEventStream, error) {
-	p, err := GetModel(model.Provider, model.ID)
+	registry, err := GetRegistry()
 	if err != nil {
-		return types.AssistantMessageEventStream{}, err
+		return types.AssistantMessageEventStream{}, fmt.Errorf("failed to initialize registry: %w", err)
 	}
-	return p.Stream(ctx, conversation), nil
+	return registry.Stream(ctx, model, conversation)
 }
 
+// Complete returns a complete response from model, which may be an Alias, using the default registry
 func Complete(ctx context.Context, model types.Model, conversation types.Context) (types.AssistantMessage, error) {
-	p, err := GetModel(model.Provider, model.ID)
+	registry, err := GetRegistry()
 	if err != nil {
-		return types.AssistantMessage{}, err
+		return types.AssistantMessage{}, fmt.Errorf("failed to initialize registry: %w", err)
 	}
-	return p.Complete(ctx, conversation)
+	return registry.Complete(ctx, model, conversation)
 }
 
-// --- Global Registry ---
+// --- Default Registry ---
 var (
-	globalRegistry atomic.Pointer[Registry]
-	globalInitOnce sync.Once
-	globalInitErr  atomic.Value
+	defaultRegistry atomic.Pointer[Registry]
+	defaultInitMu   sync.Mutex
 )
 
+// GetRegistry returns the default registry, building it from config.FromEnv on
+// first use. A failed build is retried on the next call.
 func GetRegistry() (*Registry, error) {
-	globalInitOnce.Do(func() {
-		cfg, err := config.FromEnv()
-		if err != nil {
-			globalInitErr.Store(err)
-			return
-		}
+	if registry := defaultRegistry.Load(); registry != nil {
+		return registry, nil
+	}
 
-		registry := NewRegistry()
-		if err := registry.RegisterFromConfig(cfg); err != nil {
-			globalInitErr.Store(err)
-			return
-		}
+	defaultInitMu.Lock()
+	defer defaultInitMu.Unlock()
+
+	if registry := defaultRegistry.Load(); registry != nil {
+		return registry, nil
+	}
 
-		globalRegistry.Store(registry)
-	})
+	cfg, err := config.FromEnv()
+	if err != nil {
+		return nil, err
+	}
 
-	if err, ok := globalInitErr.Load().(error); ok && err != nil {
+	registry, err := NewRegistryFromConfig(cfg)
+	if err != nil {
 		return nil, err
 	}
 
-	return globalRegistry.Load(), nil
+	defaultRegistry.Store(registry)
+	return registry, nil
+}
+
+// SetDefaultRegistry replaces the registry used by the package-level functions,
+// e.g. to inject a registry of fakes in tests
+func SetDefaultRegistry(registry *Registry) {
+	defaultRegistry.Store(registry)
+}
+
+// ResetDefaultRegistry discards the default registry; the next use rebuilds it from the environment
+func ResetDefaultRegistry() {
+	defaultRegistry.Store(nil)
 }
 
 func GetModel(providerType types.ModelProvider, modelID string) (Provider, error) {
@@ -67,53 +89,297 @@ func GetModel(providerType types.ModelProvider, modelID string) (Provider, error
 }
 
 type Registry struct {
-	models map[types.ModelProvider]map[string]Provider
-	mu     sync.RWMutex
+	models      map[types.ModelProvider]map[string]Provider
+	embedders   map[types.ModelProvider]map[string]Embedder
+	logger      *slog.Logger
+	redactor    *logging.Redactor
+	middlewares []Middleware
+	httpClient  *http.Client
+	mu          sync.RWMutex
+
+	// providerConfigs keeps registered configs and clientConfigs their
+	// resolved client settings, for discovery and lazy creation
+	providerConfigs  map[types.ModelProvider]config.ProviderConfig
+	clientConfigs    map[types.ModelProvider]openaiProvider.Config
+	owned            map[types.ModelProvider]*ownedModels
+	discover         bool
+	discoveryTimeout time.Duration
+	lazy             bool
+
+	aliases map[string]types.Model
+	routes  []route
+}
+
+// RegistryOption configures a Registry
+type RegistryOption func(*Registry)
+
+// WithLogger routes provider diagnostics to logger. API keys from registered
+// configs and the configured redaction patterns are scrubbed before output.
+func WithLogger(logger *slog.Logger) RegistryOption {
+	return func(r *Registry) {
+		r.logger = logger
+	}
+}
+
+// WithRedactPatterns adds patterns (e.g. PII) to be redacted from logged payloads
+func WithRedactPatterns(patterns ...*regexp.Regexp) RegistryOption {
+	return func(r *Registry) {
+		for _, pattern := range patterns {
+			r.redactor.AddPattern(pattern)
+		}
+	}
+}
+
+// WithMiddleware wraps every provider registered afterwards with middlewares
+func WithMiddleware(middlewares ...Middleware) RegistryOption {
+	return func(r *Registry) {
+		r.middlewares = append(r.middlewares, middlewares...)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used by providers created from config
+func WithHTTPClient(client *http.Client) RegistryOption {
+	return func(r *Registry) {
+		r.httpClient = client
+	}
 }
 
 // --- New Custom Registry ---
-func NewRegistry() *Registry {
-	return &Registry{
-		models: make(map[types.ModelProvider]map[string]Provider),
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		models:          make(map[types.ModelProvider]map[string]Provider),
+		embedders:       make(map[types.ModelProvider]map[string]Embedder),
+		redactor:        logging.NewRedactor(logging.DefaultPatterns()...),
+		providerConfigs: make(map[types.ModelProvider]config.ProviderConfig),
+		clientConfigs:   make(map[types.ModelProvider]openaiProvider.Config),
+		owned:           make(map[types.ModelProvider]*ownedModels),
+		aliases:         make(map[string]types.Model),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.logger != nil {
+		r.logger = slog.New(logging.NewHandler(r.logger.Handler(), r.redactor))
+	}
+	return r
+}
+
+// NewRegistryFromConfig creates a registry populated from cfg without reading
+// the environment
+func NewRegistryFromConfig(cfg *config.Config, opts ...RegistryOption) (*Registry, error) {
+	registry := NewRegistry(opts...)
+	if err := registry.RegisterFromConfig(cfg); err != nil {
+		return nil, err
 	}
+	return registry, nil
 }
 
+// Stream streams a response from model, which may be an Alias
+func (r *Registry) Stream(ctx context.Context, model types.Model, conversation types.Context) (types.AssistantMessageEventStream, error) {
+	p, err := r.lookup(model, conversation)
+	if err != nil {
+		return types.AssistantMessageEventStream{}, err
+	}
+	return p.Stream(ctx, conversation), nil
+}
+
+// Complete returns a complete response from model, which may be an Alias
+func (r *Registry) Complete(ctx context.Context, model types.Model, conversation types.Context) (types.AssistantMessage, error) {
+	p, err := r.lookup(model, conversation)
+	if err != nil {
+		return types.AssistantMessage{}, err
+	}
+	return p.Complete(ctx, conversation)
+}
+
+// RegisterFromConfig registers the models of every provider in cfg. With
+// WithModelDiscovery the models each endpoint lists are registered as well;
+// discovery failures are logged and do not fail registration.
 func (r *Registry) RegisterFromConfig(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := r.registerConfig(cfg); err != nil {
+		return err
+	}
+
+	if r.discover {
+		for providerName := range cfg.Providers {
+			ctx, cancel := context.WithTimeout(context.Background(), r.discoveryTimeout)
+			if _, err := r.DiscoverModels(ctx, providerName); err != nil {
+				logging.OrDiscard(r.logger).Warn("model discovery failed", "provider", string(providerName), "error", err)
+			}
+			cancel()
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) registerConfig(cfg *config.Config) error {
+	// Resolve client settings first so a bad provider leaves the registry untouched
+	clientConfigs, err := r.resolveClientConfigs(cfg)
+	if err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	for providerName, clientCfg := range clientConfigs {
+		r.registerProvider(providerName, cfg.Providers[providerName], clientCfg)
+	}
+	r.registerRouting(cfg)
+
+	return nil
+}
+
+// resolveClientConfigs resolves the client settings of every supported provider in cfg
+func (r *Registry) resolveClientConfigs(cfg *config.Config) (map[types.ModelProvider]openaiProvider.Config, error) {
+	clientConfigs := make(map[types.ModelProvider]openaiProvider.Config, len(cfg.Providers))
 	for providerName, providerCfg := range cfg.Providers {
-		switch providerName {
-		case types.ProviderNvidia:
-			for _, modelID := range providerCfg.Models {
-				p := openaiProvider.New(
-					openaiProvider.Config{
-						URL:    providerCfg.BaseURL,
-						APIKey: providerCfg.APIKey,
-					},
-					modelID,
-					types.ProviderNvidia,
-				)
-				r.register(providerName, modelID, p)
-			}
-		case types.ProviderOpenAI:
-			for _, modelID := range providerCfg.Models {
-				p := openaiProvider.New(
-					openaiProvider.Config{
-						URL:    providerCfg.BaseURL,
-						APIKey: providerCfg.APIKey,
-					},
-					modelID,
-					types.ProviderOpenAI,
-				)
-				r.register(providerName, modelID, p)
-			}
-		default:
+		if !supportsConfig(providerName) {
 			continue
 		}
+		clientCfg, err := r.clientConfig(providerName, providerCfg)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", providerName, err)
+		}
+		clientConfigs[providerName] = clientCfg
 	}
+	return clientConfigs, nil
+}
 
-	return nil
+// registerProvider registers the models and embedding models of one provider; callers hold r.mu
+func (r *Registry) registerProvider(providerName types.ModelProvider, providerCfg config.ProviderConfig, clientCfg openaiProvider.Config) {
+	r.redactor.AddSecret(providerCfg.APIKey)
+	r.providerConfigs[providerName] = providerCfg
+	r.clientConfigs[providerName] = clientCfg
+
+	for _, modelID := range providerCfg.Models {
+		r.register(providerName, modelID, r.newProvider(providerName, clientCfg, modelID))
+	}
+	for _, modelID := range providerCfg.EmbeddingModels {
+		model := types.Model{Provider: providerName, ID: modelID}
+		r.registerEmbedder(model, r.newEmbedder(providerName, clientCfg, modelID))
+	}
+}
+
+// registerRouting adds the aliases and routes of cfg; callers hold r.mu
+func (r *Registry) registerRouting(cfg *config.Config) {
+	// References were checked by cfg.Validate
+	for name, ref := range cfg.Aliases {
+		model, _ := config.ParseModelRef(ref)
+		r.aliases[name] = model
+	}
+	for _, rule := range cfg.Routes {
+		rt, _ := parseRoute(rule)
+		r.routes = append(r.routes, rt)
+	}
+}
+
+// supportsConfig reports whether providers of this type can be created from config
+func supportsConfig(providerName types.ModelProvider) bool {
+	switch providerName {
+	case types.ProviderNvidia, types.ProviderOpenAI, types.ProviderAzure:
+		return true
+	}
+	return false
+}
+
+// clientConfig resolves the client settings of a provider. The HTTP client is
+// the provider's own, one built from its proxy/TLS settings, or the registry's.
+func (r *Registry) clientConfig(providerName types.ModelProvider, providerCfg config.ProviderConfig) (openaiProvider.Config, error) {
+	httpClient := r.httpClient
+	transportOpts := transport.Options{
+		ProxyURL:           providerCfg.ProxyURL,
+		CAFile:             providerCfg.TLS.CAFile,
+		CertFile:           providerCfg.TLS.CertFile,
+		KeyFile:            providerCfg.TLS.KeyFile,
+		InsecureSkipVerify: providerCfg.TLS.InsecureSkipVerify,
+	}
+
+	switch {
+	case providerCfg.HTTPClient != nil:
+		httpClient = providerCfg.HTTPClient
+	case !transportOpts.IsZero():
+		client, err := transport.NewClient(transportOpts)
+		if err != nil {
+			return openaiProvider.Config{}, err
+		}
+		httpClient = client
+	}
+
+	clientCfg := openaiProvider.Config{
+		URL:        providerCfg.BaseURL,
+		APIKey:     providerCfg.APIKey,
+		Logger:     r.logger,
+		HTTPClient: httpClient,
+		Headers:    providerCfg.Headers,
+		Timeout:    providerCfg.Timeout,
+	}
+	if providerName == types.ProviderAzure {
+		clientCfg.AzureAPIVersion = providerCfg.APIVersion
+		if clientCfg.AzureAPIVersion == "" {
+			clientCfg.AzureAPIVersion = openaiProvider.DefaultAzureAPIVersion
+		}
+	}
+	if source := providerCfg.Credentials; source != nil {
+		clientCfg.APIKeyFunc = r.redactedCredential(source)
+	}
+	return clientCfg, nil
+}
+
+// redactedCredential resolves keys from source, redacting each key from logs
+// as soon as it is used and releasing the key it replaces on rotation
+func (r *Registry) redactedCredential(source config.CredentialSource) func(context.Context) (string, error) {
+	var (
+		mu      sync.Mutex
+		current string
+	)
+	return func(ctx context.Context) (string, error) {
+		key, err := source.Credential(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if key != current {
+			r.redactor.AddSecret(key)
+			r.redactor.RemoveSecret(current)
+			current = key
+		}
+		return key, nil
+	}
+}
+
+// newProvider creates a provider for a model of a configured provider, tracked
+// so Reload can drain it; callers hold r.mu
+func (r *Registry) newProvider(providerName types.ModelProvider, clientCfg openaiProvider.Config, modelID string) Provider {
+	owned := r.ownedBy(providerName)
+	owned.models[modelID] = true
+	return trackedProvider{Provider: openaiProvider.New(clientCfg, modelID, providerName), providerName: providerName, inflight: owned.inflight}
+}
+
+// newEmbedder creates an embedder for a model of a configured provider; callers hold r.mu
+func (r *Registry) newEmbedder(providerName types.ModelProvider, clientCfg openaiProvider.Config, modelID string) Embedder {
+	owned := r.ownedBy(providerName)
+	owned.embedders[modelID] = true
+	return trackedEmbedder{Embedder: openaiProvider.NewEmbedder(clientCfg, modelID, providerName), providerName: providerName, inflight: owned.inflight}
+}
+
+func (r *Registry) ownedBy(providerName types.ModelProvider) *ownedModels {
+	owned, ok := r.owned[providerName]
+	if !ok {
+		owned = &ownedModels{
+			models:    make(map[string]bool),
+			embedders: make(map[string]bool),
+			inflight:  &inflight{},
+		}
+		r.owned[providerName] = owned
+	}
+	return owned
 }
 
 func (r *Registry) Register(providerType types.ModelProvider, modelID string, provider Provider) error {
@@ -127,14 +393,32 @@ func (r *Registry) register(providerType types.ModelProvider, modelID string, pr
 	if _, ok := r.models[providerType]; !ok {
 		r.models[providerType] = make(map[string]Provider)
 	}
-	r.models[providerType][modelID] = provider
+	r.models[providerType][modelID] = Chain(provider, r.middlewares...)
 	return nil
 }
 
+// Get returns the provider for a model. An empty providerType treats modelID
+// as an alias, resolved without request-based routing.
 func (r *Registry) Get(providerType types.ModelProvider, modelID string) (Provider, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if providerType == "" {
+		model, err := r.resolve(modelID, requestProfile{})
+		if err != nil {
+			r.mu.RUnlock()
+			return nil, err
+		}
+		providerType, modelID = model.Provider, model.ID
+	}
+	provider, err := r.get(providerType, modelID)
+	r.mu.RUnlock()
+
+	if err != nil && r.lazy {
+		return r.getOrCreate(providerType, modelID)
+	}
+	return provider, err
+}
 
+func (r *Registry) get(providerType types.ModelProvider, modelID string) (Provider, error) {
 	providers, ok := r.models[providerType]
 	if !ok {
 		return nil, fmt.Errorf("%w: provider %s", types.ErrProviderNotFound, providerType)
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
+)
+
+func TestRedactedCredentialReleasesRotatedKeys(t *testing.T) {
+	keys := []string{"key-one", "key-one", "key-one", "key-two"}
+	source := config.CredentialFunc(func(context.Context) (string, error) {
+		key := keys[0]
+		keys = keys[1:]
+		return key, nil
+	})
+	registry := NewRegistry()
+	apiKey := registry.redactedCredential(source)
+
+	for range 3 {
+		if _, err := apiKey(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := registry.redactor.Redact("key-one"); got != logging.Placeholder {
+		t.Errorf("Redact = %q, want the key in use redacted", got)
+	}
+
+	if _, err := apiKey(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := registry.redactor.Redact("key-one key-two"); got != "key-one "+logging.Placeholder {
+		t.Errorf("Redact = %q, want only the current key redacted", got)
+	}
+}
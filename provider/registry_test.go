@@ -0,0 +1,54 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func TestRegistryRedactsLoggedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id": "chatcmpl-1", "object": "chat.completion", "created": 1, "model": "gpt-4o",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`)
+	}))
+	defer server.Close()
+
+	const key = "custom-key-abcdef"
+	cfg := config.NewConfig()
+	cfg.SetProvider(types.ProviderOpenAI, config.ProviderConfig{BaseURL: server.URL, APIKey: key, Models: []string{"gpt-4o"}})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	registry, err := provider.NewRegistryFromConfig(cfg,
+		provider.WithLogger(logger),
+		provider.WithRedactPatterns(regexp.MustCompile(`ACCT-\d+`)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := types.Model{Provider: types.ProviderOpenAI, ID: "gpt-4o"}
+	conversation := userMessage(types.TextContent{Text: "close ACCT-42, my key is " + key})
+	if _, err := registry.Complete(context.Background(), model, conversation); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "sending request") {
+		t.Fatalf("request body not logged: %s", out)
+	}
+	if strings.Contains(out, key) || strings.Contains(out, "ACCT-42") {
+		t.Errorf("secret logged: %s", out)
+	}
+}
@@ -0,0 +1,365 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	openaiProvider "github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/logging"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// defaultDrainTimeout bounds how long WatchConfig waits for in-flight requests
+const defaultDrainTimeout = 30 * time.Second
+
+// ReloadResult lists the providers a Reload changed
+type ReloadResult struct {
+	Added   []types.ModelProvider
+	Removed []types.ModelProvider
+	// Changed providers were rebuilt from their new config
+	Changed []types.ModelProvider
+}
+
+// Reload applies cfg to a running registry. cfg is validated and its clients
+// are built before anything changes, so an invalid config leaves the registry
+// as it was. Providers that were added or whose config changed are
+// (re)registered, removed ones are unregistered, and unchanged providers keep
+// their models, including discovered ones. Aliases and routes are replaced by
+// those in cfg; models registered with Register are kept.
+//
+// Requests already running on removed or changed providers finish normally;
+// Reload waits for them until ctx is done. An error from draining means cfg
+// has been applied. Providers obtained before the Reload fail new requests
+// with types.ErrProviderNotFound once their config is removed or changed; get
+// them from the registry again.
+func (r *Registry) Reload(ctx context.Context, cfg *config.Config) (ReloadResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return ReloadResult{}, err
+	}
+	clientConfigs, err := r.resolveClientConfigs(cfg)
+	if err != nil {
+		return ReloadResult{}, err
+	}
+
+	r.mu.Lock()
+
+	var (
+		result   ReloadResult
+		draining []*inflight
+	)
+	for providerName, oldCfg := range r.providerConfigs {
+		newCfg, ok := cfg.Providers[providerName]
+		switch {
+		case !ok || !supportsConfig(providerName):
+			result.Removed = append(result.Removed, providerName)
+		case !sameProviderConfig(oldCfg, newCfg):
+			result.Changed = append(result.Changed, providerName)
+		default:
+			continue
+		}
+		if owned := r.unregisterProvider(providerName); owned != nil {
+			owned.inflight.close()
+			draining = append(draining, owned.inflight)
+		}
+	}
+
+	for providerName, clientCfg := range clientConfigs {
+		if _, ok := r.providerConfigs[providerName]; ok {
+			continue
+		}
+		if !contains(result.Changed, providerName) {
+			result.Added = append(result.Added, providerName)
+		}
+		r.registerProvider(providerName, cfg.Providers[providerName], clientCfg)
+	}
+
+	r.aliases = make(map[string]types.Model, len(cfg.Aliases))
+	r.routes = nil
+	r.registerRouting(cfg)
+
+	r.mu.Unlock()
+
+	sortProviders(result.Added)
+	sortProviders(result.Removed)
+	sortProviders(result.Changed)
+
+	if r.discover {
+		for _, providerName := range append(append([]types.ModelProvider{}, result.Added...), result.Changed...) {
+			discoverCtx, cancel := context.WithTimeout(ctx, r.discoveryTimeout)
+			if _, err := r.DiscoverModels(discoverCtx, providerName); err != nil {
+				logging.OrDiscard(r.logger).Warn("model discovery failed", "provider", string(providerName), "error", err)
+			}
+			cancel()
+		}
+	}
+
+	for _, requests := range draining {
+		if err := requests.wait(ctx); err != nil {
+			return result, fmt.Errorf("drain in-flight requests: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// WatchConfig polls the file at path every interval and reloads the registry
+// with load(path) whenever it changes, e.g. with config.FromEnvFile. Invalid
+// configs are logged and the running config is kept. It blocks until ctx is done.
+func (r *Registry) WatchConfig(ctx context.Context, path string, interval time.Duration, load func(path string) (*config.Config, error)) error {
+	logger := logging.OrDiscard(r.logger)
+
+	last, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("watch config: %w", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Warn("config watch failed", "path", path, "error", err)
+			continue
+		}
+		if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+
+		cfg, err := load(path)
+		if err != nil {
+			logger.Error("config reload rejected", "path", path, "error", err)
+			continue
+		}
+
+		drainCtx, cancel := context.WithTimeout(ctx, defaultDrainTimeout)
+		result, err := r.Reload(drainCtx, cfg)
+		cancel()
+		if err != nil {
+			logger.Error("config reload failed", "path", path, "error", err)
+			continue
+		}
+		logger.Info("config reloaded",
+			"path", path,
+			"added", result.Added,
+			"removed", result.Removed,
+			"changed", result.Changed,
+		)
+	}
+}
+
+// unregisterProvider removes everything a provider config registered and
+// returns its tracking state; callers hold r.mu
+func (r *Registry) unregisterProvider(providerName types.ModelProvider) *ownedModels {
+	owned := r.owned[providerName]
+	if owned != nil {
+		for modelID := range owned.models {
+			delete(r.models[providerName], modelID)
+		}
+		for modelID := range owned.embedders {
+			delete(r.embedders[providerName], modelID)
+		}
+	}
+	if len(r.models[providerName]) == 0 {
+		delete(r.models, providerName)
+	}
+	if len(r.embedders[providerName]) == 0 {
+		delete(r.embedders, providerName)
+	}
+
+	delete(r.owned, providerName)
+	delete(r.providerConfigs, providerName)
+	delete(r.clientConfigs, providerName)
+	return owned
+}
+
+// sameProviderConfig compares configs by value; HTTP clients are compared by
+// identity and credential sources with sameCredentials
+func sameProviderConfig(a, b config.ProviderConfig) bool {
+	if a.HTTPClient != b.HTTPClient || !sameCredentials(a.Credentials, b.Credentials) {
+		return false
+	}
+	a.HTTPClient, b.HTTPClient = nil, nil
+	a.Credentials, b.Credentials = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// sameCredentials reports whether a and b supply the same credential, using
+// their Equal method when they have one; other sources of uncomparable types,
+// such as CredentialFunc, never match
+func sameCredentials(a, b config.CredentialSource) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if equaler, ok := a.(interface {
+		Equal(config.CredentialSource) bool
+	}); ok {
+		return equaler.Equal(b)
+	}
+	if !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func contains(providers []types.ModelProvider, providerName types.ModelProvider) bool {
+	for _, p := range providers {
+		if p == providerName {
+			return true
+		}
+	}
+	return false
+}
+
+func sortProviders(providers []types.ModelProvider) {
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+}
+
+// ownedModels records what a provider config registered, so Reload can
+// remove it and wait for its in-flight requests
+type ownedModels struct {
+	models    map[string]bool
+	embedders map[string]bool
+	inflight  *inflight
+}
+
+// inflight counts running requests; once closed it admits no new ones
+type inflight struct {
+	mu      sync.Mutex
+	n       int
+	closed  bool
+	waiters []chan struct{}
+}
+
+// start admits a request and reports false if the provider was removed
+func (f *inflight) start() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return false
+	}
+	f.n++
+	return true
+}
+
+// close stops admitting requests, so a drained provider stays drained
+func (f *inflight) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+}
+
+func (f *inflight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.n--
+	if f.n == 0 {
+		for _, waiter := range f.waiters {
+			close(waiter)
+		}
+		f.waiters = nil
+	}
+}
+
+// wait blocks until no requests are running or ctx is done
+func (f *inflight) wait(ctx context.Context) error {
+	f.mu.Lock()
+	if f.n == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	f.waiters = append(f.waiters, idle)
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trackedProvider counts the requests running on a provider created from config
+type trackedProvider struct {
+	Provider
+	providerName types.ModelProvider
+	inflight     *inflight
+}
+
+func (p trackedProvider) Unwrap() Provider {
+	return p.Provider
+}
+
+func (p trackedProvider) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
+	if !p.inflight.start() {
+		return failedStream(p.providerName, errRemoved(p.providerName))
+	}
+	return InterceptStream(p.Provider.Stream(ctx, conversation), nil, func(types.AssistantMessage, error) {
+		p.inflight.done()
+	})
+}
+
+func (p trackedProvider) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	if !p.inflight.start() {
+		return types.AssistantMessage{}, errRemoved(p.providerName)
+	}
+	defer p.inflight.done()
+
+	return p.Provider.Complete(ctx, conversation)
+}
+
+// trackedEmbedder counts the requests running on an embedder created from config
+type trackedEmbedder struct {
+	*openaiProvider.Embedder
+	providerName types.ModelProvider
+	inflight     *inflight
+}
+
+func (e trackedEmbedder) Embed(ctx context.Context, inputs []string, opts types.EmbedOptions) ([][]float32, types.Usage, error) {
+	if !e.inflight.start() {
+		return nil, types.Usage{}, errRemoved(e.providerName)
+	}
+	defer e.inflight.done()
+
+	return e.Embedder.Embed(ctx, inputs, opts)
+}
+
+func errRemoved(providerName types.ModelProvider) error {
+	return fmt.Errorf("%w: provider %s was removed or reconfigured by Reload", types.ErrProviderNotFound, providerName)
+}
+
+// failedStream returns a stream that ends with err without sending a request
+func failedStream(providerName types.ModelProvider, err error) types.AssistantMessageEventStream {
+	stream := types.NewAssistantMessageEventStream()
+	go func() {
+		errMsg := err.Error()
+		output := types.AssistantMessage{
+			Contents:     []types.Content{},
+			Timestamp:    time.Now(),
+			Provider:     providerName,
+			ErrorMessage: &errMsg,
+			StopReason:   types.StopReasonError,
+		}
+		stream.Events <- types.EventError{Reason: output.StopReason, Error: output}
+		stream.Result <- output
+		stream.Err <- err
+		stream.Close()
+	}()
+	return stream
+}
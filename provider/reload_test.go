@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func reloadConfig(providers map[types.ModelProvider]config.ProviderConfig) *config.Config {
+	cfg := config.NewConfig()
+	for name, providerCfg := range providers {
+		cfg.SetProvider(name, providerCfg)
+	}
+	return cfg
+}
+
+func TestReloadDiffsProviders(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	initial := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {APIKey: "sk-test", Models: []string{"gpt-4o"}},
+		types.ProviderNvidia: {Credentials: config.FileCredential(keyFile), Models: []string{"llama"}},
+	})
+	registry, err := NewRegistryFromConfig(initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Credential sources are rebuilt on every load; the same file is the same source
+	result, err := registry.Reload(context.Background(), reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {APIKey: "sk-test", Models: []string{"gpt-4o"}},
+		types.ProviderNvidia: {Credentials: config.FileCredential(keyFile), Models: []string{"llama"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, ReloadResult{}) {
+		t.Errorf("unchanged config reloaded as %+v", result)
+	}
+
+	result, err = registry.Reload(context.Background(), reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {APIKey: "sk-rotated", Models: []string{"gpt-4o"}},
+		types.ProviderAzure:  {BaseURL: "https://example.openai.azure.com", APIKey: "azure", Models: []string{"prod"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ReloadResult{
+		Added:   []types.ModelProvider{types.ProviderAzure},
+		Removed: []types.ModelProvider{types.ProviderNvidia},
+		Changed: []types.ModelProvider{types.ProviderOpenAI},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Reload = %+v, want %+v", result, want)
+	}
+	if _, err := registry.Get(types.ProviderNvidia, "llama"); !errors.Is(err, types.ErrProviderNotFound) {
+		t.Errorf("removed provider: Get error = %v", err)
+	}
+	if _, err := registry.Get(types.ProviderAzure, "prod"); err != nil {
+		t.Errorf("added provider: %v", err)
+	}
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id": "chatcmpl-1", "object": "chat.completion", "created": 1, "model": "gpt-4o",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`)
+	}))
+	defer server.Close()
+
+	good := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {BaseURL: server.URL, APIKey: "sk-test", Models: []string{"gpt-4o"}},
+	})
+	good.Aliases["fast"] = "openai:gpt-4o"
+	registry, err := NewRegistryFromConfig(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noKey := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {BaseURL: server.URL, Models: []string{"gpt-4o"}},
+	})
+	noEndpoint := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {BaseURL: server.URL, APIKey: "sk-test", Models: []string{"gpt-4o"}},
+		types.ProviderAzure:  {APIKey: "azure", Models: []string{"prod"}},
+	})
+	unknownProvider := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {BaseURL: server.URL, APIKey: "sk-test", Models: []string{"gpt-4o"}},
+	})
+	unknownProvider.Aliases["fast"] = "mistral:nope"
+	unknownRoute := reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {BaseURL: server.URL, APIKey: "sk-test", Models: []string{"gpt-4o"}},
+	})
+	unknownRoute.Aliases["fast"] = "openai:gpt-4o"
+	unknownRoute.Routes = []config.RouteRule{{Alias: "fast", Model: "mistral:large", HasTools: true}}
+
+	for name, cfg := range map[string]*config.Config{
+		"no credentials":    noKey,
+		"azure without URL": noEndpoint,
+		"alias to unknown":  unknownProvider,
+		"route to unknown":  unknownRoute,
+	} {
+		if _, err := registry.Reload(context.Background(), cfg); !errors.Is(err, types.ErrConfigInvalid) {
+			t.Errorf("%s: Reload error = %v, want ErrConfigInvalid", name, err)
+		}
+	}
+
+	p, err := registry.Get("", "fast")
+	if err != nil {
+		t.Fatalf("alias lost after rejected reloads: %v", err)
+	}
+	if _, err := p.Complete(context.Background(), types.Context{}); err != nil {
+		t.Errorf("old provider stopped serving: %v", err)
+	}
+}
+
+func TestReloadFailsRequestsOnRemovedProvider(t *testing.T) {
+	registry, err := NewRegistryFromConfig(reloadConfig(map[types.ModelProvider]config.ProviderConfig{
+		types.ProviderOpenAI: {APIKey: "sk-test", Models: []string{"gpt-4o"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := registry.Get(types.ProviderOpenAI, "gpt-4o")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := registry.Reload(context.Background(), config.NewConfig()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stale.Complete(context.Background(), types.Context{}); !errors.Is(err, types.ErrProviderNotFound) {
+		t.Errorf("Complete error = %v, want ErrProviderNotFound", err)
+	}
+	stream := stale.Stream(context.Background(), types.Context{})
+	for range stream.Events {
+	}
+	<-stream.Result
+	if err := <-stream.Err; !errors.Is(err, types.ErrProviderNotFound) {
+		t.Errorf("Stream error = %v, want ErrProviderNotFound", err)
+	}
+}
+
+func TestInflightWaitsForRunningRequests(t *testing.T) {
+	requests := &inflight{}
+	if !requests.start() {
+		t.Fatal("start refused before close")
+	}
+	requests.close()
+	if requests.start() {
+		t.Fatal("start admitted a request after close")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := requests.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait with a running request = %v", err)
+	}
+
+	requests.done()
+	if err := requests.wait(context.Background()); err != nil {
+		t.Fatalf("wait after done = %v", err)
+	}
+}
+
+func TestSameCredentials(t *testing.T) {
+	fetch := func(context.Context) (config.Token, error) { return config.Token{Value: "token"}, nil }
+	refreshing := config.RefreshingCredential(fetch)
+
+	tests := []struct {
+		name string
+		a, b config.CredentialSource
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"one nil", config.StaticCredential("key"), nil, false},
+		{"same static key", config.StaticCredential("key"), config.StaticCredential("key"), true},
+		{"different static keys", config.StaticCredential("key"), config.StaticCredential("other"), false},
+		{"same env variable", config.EnvCredential("KEY"), config.EnvCredential("KEY"), true},
+		{"same file", config.FileCredential("/run/secrets/key"), config.FileCredential("/run/secrets/key"), true},
+		{"different files", config.FileCredential("/run/secrets/key"), config.FileCredential("/run/secrets/other"), false},
+		{"file and env", config.FileCredential("KEY"), config.EnvCredential("KEY"), false},
+		{"same refreshing source", refreshing, refreshing, true},
+		{"new refreshing source", refreshing, config.RefreshingCredential(fetch), false},
+		{"function", config.CredentialFunc(fetchKey), config.CredentialFunc(fetchKey), false},
+	}
+	for _, tt := range tests {
+		if got := sameCredentials(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: sameCredentials = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func fetchKey(context.Context) (string, error) {
+	return "key", nil
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Alias returns a model reference that the registry resolves through its
+// aliases and routing rules, e.g. provider.Stream(ctx, provider.Alias("fast"), conversation)
+func Alias(name string) types.Model {
+	return types.Model{ID: name}
+}
+
+// route is a parsed config.RouteRule
+type route struct {
+	alias     string
+	model     types.Model
+	hasImages bool
+	hasTools  bool
+	minTokens int
+}
+
+func (rt route) matches(profile requestProfile) bool {
+	return (!rt.hasImages || profile.hasImages) &&
+		(!rt.hasTools || profile.hasTools) &&
+		profile.tokens >= rt.minTokens
+}
+
+// requestProfile holds the request properties routing rules look at
+type requestProfile struct {
+	hasImages bool
+	hasTools  bool
+	tokens    int
+}
+
+func profileOf(conversation types.Context) requestProfile {
+	profile := requestProfile{
+		hasTools: len(conversation.Tools) > 0,
+		tokens:   EstimateTokens(conversation),
+	}
+	for _, message := range conversation.Messages {
+		var contents []types.Content
+		switch msg := message.(type) {
+		case types.UserMessage:
+			contents = msg.Contents
+		case types.ToolMessage:
+			contents = msg.Contents
+		}
+		for _, content := range contents {
+			if _, ok := content.(types.ImageContent); ok {
+				profile.hasImages = true
+			}
+		}
+	}
+	return profile
+}
+
+const (
+	// imageTokens approximates one image, a 1024x1024 image at high detail
+	imageTokens = 765
+	// audioTokens approximates one audio clip, about a minute of speech
+	audioTokens = 600
+)
+
+// EstimateTokens roughly estimates the prompt size of conversation at four
+// characters per token, plus a flat amount per image and audio clip
+func EstimateTokens(conversation types.Context) int {
+	chars := len(conversation.SystemPrompt)
+	media := 0
+
+	for _, tool := range conversation.Tools {
+		chars += len(tool.Name) + len(tool.Description)
+		if schema, err := json.Marshal(tool.Parameters); err == nil {
+			chars += len(schema)
+		}
+	}
+
+	for _, message := range conversation.Messages {
+		var contents []types.Content
+		switch msg := message.(type) {
+		case types.UserMessage:
+			contents = msg.Contents
+		case types.AssistantMessage:
+			contents = msg.Contents
+		case types.ToolMessage:
+			contents = msg.Contents
+		}
+		for _, content := range contents {
+			switch c := content.(type) {
+			case types.TextContent:
+				chars += len(c.Text)
+			case types.ToolCall:
+				chars += len(c.Name) + len(c.RawArguments)
+			case types.DocumentContent:
+				if text, err := c.Text(); err == nil {
+					chars += len(text)
+				}
+			case types.JSONContent:
+				if data, err := json.Marshal(c.Value); err == nil {
+					chars += len(data)
+				}
+			case types.ImageContent:
+				media += imageTokens
+			case types.AudioContent:
+				media += audioTokens
+			}
+		}
+	}
+
+	return (chars+3)/4 + media
+}
+
+// SetAlias points name at model, replacing any previous target
+func (r *Registry) SetAlias(name string, model types.Model) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.aliases[name] = model
+}
+
+// AddRoute appends a routing rule for an existing alias
+func (r *Registry) AddRoute(rule config.RouteRule) error {
+	rt, err := parseRoute(rule)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.aliases[rule.Alias]; !ok {
+		return fmt.Errorf("%w: alias %s", types.ErrModelNotFound, rule.Alias)
+	}
+	r.routes = append(r.routes, rt)
+	return nil
+}
+
+// Aliases returns the configured aliases and their default targets
+func (r *Registry) Aliases() map[string]types.Model {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(map[string]types.Model, len(r.aliases))
+	for name, model := range r.aliases {
+		aliases[name] = model
+	}
+	return aliases
+}
+
+// Resolve maps an alias reference (a Model without a Provider) to a concrete
+// model, applying routing rules to conversation. Concrete models are returned unchanged.
+func (r *Registry) Resolve(model types.Model, conversation types.Context) (types.Model, error) {
+	if model.Provider != "" {
+		return model, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.resolve(model.ID, profileOf(conversation))
+}
+
+func (r *Registry) resolve(alias string, profile requestProfile) (types.Model, error) {
+	target, ok := r.aliases[alias]
+	if !ok {
+		return types.Model{}, fmt.Errorf("%w: alias %s", types.ErrModelNotFound, alias)
+	}
+
+	for _, rt := range r.routes {
+		if rt.alias == alias && rt.matches(profile) {
+			return rt.model, nil
+		}
+	}
+	return target, nil
+}
+
+// lookup resolves model for conversation and returns its provider
+func (r *Registry) lookup(model types.Model, conversation types.Context) (Provider, error) {
+	resolved, err := r.Resolve(model, conversation)
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(resolved.Provider, resolved.ID)
+}
+
+func parseRoute(rule config.RouteRule) (route, error) {
+	model, err := config.ParseModelRef(rule.Model)
+	if err != nil {
+		return route{}, err
+	}
+	return route{
+		alias:     rule.Alias,
+		model:     model,
+		hasImages: rule.HasImages,
+		hasTools:  rule.HasTools,
+		minTokens: rule.MinTokens,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package provider_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/config"
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func userMessage(contents ...types.Content) types.Context {
+	return types.Context{Messages: []types.Message{types.UserMessage{Contents: contents}}}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	text := types.TextContent{Text: strings.Repeat("a", 400)}
+	image := types.ImageContent{URL: "https://example.com/cat.png"}
+	audio := types.AudioContent{Format: "wav", Data: "UklGRg=="}
+
+	tests := []struct {
+		name         string
+		conversation types.Context
+		want         int
+	}{
+		{"empty", types.Context{}, 0},
+		{"text", userMessage(text), 100},
+		{"system prompt", types.Context{SystemPrompt: strings.Repeat("a", 40)}, 10},
+		{"json", userMessage(types.JSONContent{Value: map[string]string{"k": "v"}}), 3},
+		{"image", userMessage(text, image), 100 + 765},
+		{"images", userMessage(image, image), 2 * 765},
+		{"audio", userMessage(audio), 600},
+	}
+	for _, tt := range tests {
+		if got := provider.EstimateTokens(tt.conversation); got != tt.want {
+			t.Errorf("%s: EstimateTokens = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	fast := types.Model{Provider: types.ProviderOpenAI, ID: "gpt-4o-mini"}
+	registry := provider.NewRegistry()
+	registry.SetAlias("fast", fast)
+	for _, rule := range []config.RouteRule{
+		{Alias: "fast", Model: "openai:gpt-4o", HasImages: true},
+		{Alias: "fast", Model: "openai:gpt-4.1", MinTokens: 1000},
+		{Alias: "fast", Model: "nvidia:tool-model", HasTools: true},
+	} {
+		if err := registry.AddRoute(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	long := userMessage(types.TextContent{Text: strings.Repeat("a", 4000)})
+	withTools := userMessage(types.TextContent{Text: "hi"})
+	withTools.Tools = []types.Tool{{Name: "search"}}
+
+	tests := []struct {
+		name         string
+		conversation types.Context
+		want         string
+	}{
+		{"default", userMessage(types.TextContent{Text: "hi"}), "gpt-4o-mini"},
+		{"images", userMessage(types.ImageContent{URL: "https://example.com/cat.png"}), "gpt-4o"},
+		{"long prompt", long, "gpt-4.1"},
+		{"tools", withTools, "tool-model"},
+		// Routes are tried in order, so images win over a long prompt
+		{"first match", userMessage(types.ImageContent{URL: "https://example.com/cat.png"}, long.Messages[0].Content()[0]), "gpt-4o"},
+	}
+	for _, tt := range tests {
+		got, err := registry.Resolve(provider.Alias("fast"), tt.conversation)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.ID != tt.want {
+			t.Errorf("%s: Resolve = %s, want %s", tt.name, got.ID, tt.want)
+		}
+	}
+
+	if got, _ := registry.Resolve(fast, long); got != fast {
+		t.Errorf("concrete model resolved to %v", got)
+	}
+	if _, err := registry.Resolve(provider.Alias("slow"), types.Context{}); !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("unknown alias error = %v", err)
+	}
+	if err := registry.AddRoute(config.RouteRule{Alias: "slow", Model: "openai:o3"}); !errors.Is(err, types.ErrModelNotFound) {
+		t.Errorf("route for unknown alias error = %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/jsonschema"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// schemaNameInvalid matches characters not allowed in response format names
+var schemaNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// JSONOption configures CompleteJSON
+type JSONOption func(*jsonOptions)
+
+type jsonOptions struct {
+	name        string
+	description string
+	strict      bool
+	reprompts   int
+	registry    *Registry
+}
+
+// WithRegistry sends the request through registry instead of the default registry
+func WithRegistry(registry *Registry) JSONOption {
+	return func(o *jsonOptions) {
+		o.registry = registry
+	}
+}
+
+// WithSchemaName sets the schema name sent to the provider (default: the Go type name)
+func WithSchemaName(name string) JSONOption {
+	return func(o *jsonOptions) {
+		o.name = name
+	}
+}
+
+// WithSchemaDescription describes the expected output to the model
+func WithSchemaDescription(description string) JSONOption {
+	return func(o *jsonOptions) {
+		o.description = description
+	}
+}
+
+// WithStrictSchema toggles provider-side strict schema enforcement (default
+// true). Types strict mode cannot describe, such as maps and recursive
+// structs, are always sent without it.
+func WithStrictSchema(strict bool) JSONOption {
+	return func(o *jsonOptions) {
+		o.strict = strict
+	}
+}
+
+// WithReprompts re-asks the model up to n times when its answer fails validation
+func WithReprompts(n int) JSONOption {
+	return func(o *jsonOptions) {
+		o.reprompts = n
+	}
+}
+
+// CompleteJSON asks model for JSON matching the schema derived from T, which
+// must be a struct or a pointer to one, and decodes the answer. The returned
+// message is the last assistant reply.
+func CompleteJSON[T any](ctx context.Context, model types.Model, conversation types.Context, opts ...JSONOption) (T, types.AssistantMessage, error) {
+	var result T
+
+	t := reflect.TypeOf(result)
+	if t == nil {
+		return result, types.AssistantMessage{}, fmt.Errorf("CompleteJSON requires a concrete type parameter")
+	}
+	if t.Kind() != reflect.Struct && (t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct) {
+		return result, types.AssistantMessage{}, fmt.Errorf("%w: CompleteJSON requires a struct type, got %s", types.ErrNotSupported, t)
+	}
+
+	options := jsonOptions{
+		name:   schemaName(t),
+		strict: true,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	schema, err := jsonschema.Generate(t, options.strict)
+	if errors.Is(err, jsonschema.ErrNotStrict) {
+		options.strict = false
+		schema, err = jsonschema.Generate(t, false)
+	}
+	if err != nil {
+		return result, types.AssistantMessage{}, err
+	}
+	conversation.ResponseFormat = &types.ResponseFormat{
+		Type:        types.ResponseFormatJSONSchema,
+		Name:        options.name,
+		Description: options.description,
+		Schema:      schema,
+		Strict:      options.strict,
+	}
+	conversation.Messages = append([]types.Message{}, conversation.Messages...)
+
+	for attempt := 0; ; attempt++ {
+		var message types.AssistantMessage
+		var err error
+		if options.registry != nil {
+			message, err = options.registry.Complete(ctx, model, conversation)
+		} else {
+			message, err = Complete(ctx, model, conversation)
+		}
+		if err != nil {
+			return result, message, err
+		}
+
+		decodeErr := decodeJSON(messageText(message), schema, &result)
+		if decodeErr == nil {
+			return result, message, nil
+		}
+		if attempt >= options.reprompts {
+			return result, message, fmt.Errorf("%w: %v", types.ErrInvalidStructuredOutput, decodeErr)
+		}
+
+		conversation.Messages = append(conversation.Messages,
+			message,
+			types.UserMessage{
+				Timestamp: time.Now(),
+				Contents: []types.Content{types.TextContent{
+					Text: fmt.Sprintf("Your previous answer did not match the required JSON schema: %v. Reply again with only the corrected JSON.", decodeErr),
+				}},
+			},
+		)
+	}
+}
+
+func decodeJSON(text string, schema map[string]any, dst any) error {
+	text = stripCodeFence(text)
+
+	var raw any
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if err := jsonschema.Validate(schema, raw); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(text), dst)
+}
+
+// stripCodeFence removes a surrounding ```json fence some models add
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+
+	text = strings.TrimPrefix(text, "```")
+	if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+		text = text[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+}
+
+func messageText(message types.AssistantMessage) string {
+	var sb strings.Builder
+	for _, content := range message.Contents {
+		if text, ok := content.(types.TextContent); ok {
+			sb.WriteString(text.Text)
+		}
+	}
+	return sb.String()
+}
+
+func schemaName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	name := schemaNameInvalid.ReplaceAllString(t.Name(), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
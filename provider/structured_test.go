@@ -0,0 +1,84 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/providertest"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+type labelled struct {
+	Labels map[string]string `json:"labels"`
+	Owner  *string           `json:"owner"`
+}
+
+var fakeModel = types.Model{Provider: types.ProviderCustom, ID: "fake"}
+
+func fakeRegistry(t *testing.T, responses ...providertest.Response) (*provider.Registry, *providertest.Fake) {
+	t.Helper()
+	fake := providertest.New(fakeModel.Provider, fakeModel.ID, responses...)
+	registry := provider.NewRegistry()
+	if err := registry.Register(fakeModel.Provider, fakeModel.ID, fake); err != nil {
+		t.Fatal(err)
+	}
+	return registry, fake
+}
+
+func TestCompleteJSON(t *testing.T) {
+	registry, fake := fakeRegistry(t,
+		providertest.Text(`{"city": "Paris"}`),
+		providertest.Text("```json\n{\"city\": \"Paris\", \"temperature\": 21.5}\n```"),
+	)
+
+	got, _, err := provider.CompleteJSON[weather](context.Background(), fakeModel, types.Context{}, provider.WithRegistry(registry), provider.WithReprompts(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (weather{City: "Paris", Temperature: 21.5}); got != want {
+		t.Errorf("CompleteJSON = %+v, want %+v", got, want)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("got %d requests, want 2", len(calls))
+	}
+	format := calls[0].ResponseFormat
+	if format == nil || !format.Strict || format.Name != "weather" {
+		t.Errorf("response format = %+v, want strict weather schema", format)
+	}
+}
+
+func TestCompleteJSONFallsBackFromStrict(t *testing.T) {
+	registry, fake := fakeRegistry(t, providertest.Text(`{"labels": {"env": "prod"}, "owner": null}`))
+
+	got, _, err := provider.CompleteJSON[labelled](context.Background(), fakeModel, types.Context{}, provider.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Labels["env"] != "prod" || got.Owner != nil {
+		t.Errorf("CompleteJSON = %+v", got)
+	}
+	if format := fake.Calls()[0].ResponseFormat; format.Strict {
+		t.Error("map type sent with a strict schema")
+	}
+}
+
+func TestCompleteJSONRequiresStruct(t *testing.T) {
+	registry, fake := fakeRegistry(t)
+
+	_, _, err := provider.CompleteJSON[[]weather](context.Background(), fakeModel, types.Context{}, provider.WithRegistry(registry))
+	if !errors.Is(err, types.ErrNotSupported) {
+		t.Errorf("CompleteJSON error = %v, want ErrNotSupported", err)
+	}
+	if len(fake.Calls()) != 0 {
+		t.Error("request sent for a non-struct type")
+	}
+}
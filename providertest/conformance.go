@@ -0,0 +1,368 @@
+package providertest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// streamTimeout bounds how long the suite waits for a stream to finish
+const streamTimeout = 10 * time.Second
+
+// Factory creates the provider under test, configured to talk to server
+type Factory func(t *testing.T, server *MockServer) provider.Provider
+
+// RunConformance checks that a provider follows the streaming contract shared
+// by all providers: event ordering, content indices, stop reasons, tool-call
+// parsing, error reporting and cancellation.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Run("StreamText", func(t *testing.T) {
+		server := NewMockServer(t)
+		server.Push(Text("Hello from the conformance suite!").WithUsage(types.Usage{InputTokens: 5, OutputTokens: 7, TotalTokens: 12}))
+
+		events, result, err := collect(t, factory(t, server).Stream(context.Background(), conversation()))
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		checkSequence(t, events, result)
+		checkStopReason(t, result, types.StopReasonStop)
+		checkContents(t, result, types.TextContent{Text: "Hello from the conformance suite!"})
+	})
+
+	t.Run("StreamToolCall", func(t *testing.T) {
+		call := types.ToolCall{ID: "call_1", Name: "getWeather", Arguments: map[string]any{"location": "Tokyo", "days": 3.0}}
+		server := NewMockServer(t)
+		server.Push(Response{
+			Contents: []types.Content{types.TextContent{Text: "Let me check."}, call},
+		})
+
+		events, result, err := collect(t, factory(t, server).Stream(context.Background(), conversation()))
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		checkSequence(t, events, result)
+		checkStopReason(t, result, types.StopReasonToolUse)
+		checkContents(t, result, types.TextContent{Text: "Let me check."}, call)
+	})
+
+	t.Run("StreamParallelToolCalls", func(t *testing.T) {
+		tokyo := types.ToolCall{ID: "call_1", Name: "getWeather", Arguments: map[string]any{"location": "Tokyo"}}
+		paris := types.ToolCall{ID: "call_2", Name: "getWeather", Arguments: map[string]any{"location": "Paris", "days": 2.0}}
+		server := NewMockServer(t)
+		server.Push(ToolCalls(tokyo, paris).WithChunkSize(5))
+
+		events, result, err := collect(t, factory(t, server).Stream(context.Background(), conversation()))
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		checkSequence(t, events, result)
+		checkStopReason(t, result, types.StopReasonToolUse)
+		checkContents(t, result, tokyo, paris)
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		call := types.ToolCall{ID: "call_1", Name: "getWeather", Arguments: map[string]any{"location": "Paris"}}
+		server := NewMockServer(t)
+		server.Push(Response{
+			Contents: []types.Content{types.TextContent{Text: "Checking."}, call},
+		})
+
+		result, err := factory(t, server).Complete(context.Background(), conversation())
+		if err != nil {
+			t.Fatalf("unexpected completion error: %v", err)
+		}
+		checkStopReason(t, result, types.StopReasonToolUse)
+		checkContents(t, result, types.TextContent{Text: "Checking."}, call)
+	})
+
+	t.Run("StreamServerError", func(t *testing.T) {
+		server := NewMockServer(t)
+		server.Push(Error(&HTTPError{StatusCode: 400, Message: "bad request"}))
+
+		events, result, err := collect(t, factory(t, server).Stream(context.Background(), conversation()))
+		if err == nil {
+			t.Fatal("expected stream error, got nil")
+		}
+		checkFailure(t, events, result, types.StopReasonError)
+	})
+
+	t.Run("CompleteServerError", func(t *testing.T) {
+		server := NewMockServer(t)
+		server.Push(Error(&HTTPError{StatusCode: 400, Message: "bad request"}))
+
+		if _, err := factory(t, server).Complete(context.Background(), conversation()); err == nil {
+			t.Fatal("expected completion error, got nil")
+		}
+	})
+
+	t.Run("StreamMidStreamFailure", func(t *testing.T) {
+		server := NewMockServer(t)
+		server.Push(Text("this stream breaks halfway").WithChunkSize(3).FailingAfter(2, errors.New("upstream disconnected")))
+
+		events, result, err := collect(t, factory(t, server).Stream(context.Background(), conversation()))
+		if err == nil {
+			t.Fatal("expected stream error, got nil")
+		}
+		checkFailure(t, events, result, types.StopReasonError)
+	})
+
+	t.Run("StreamCancellation", func(t *testing.T) {
+		server := NewMockServer(t)
+		server.Push(Text("slow answer that never completes").WithChunkSize(1).WithDelay(time.Second))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		started := time.Now()
+		events, result, err := collect(t, factory(t, server).Stream(ctx, conversation()))
+		if err == nil {
+			t.Fatal("expected cancellation error, got nil")
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", err)
+		}
+		if elapsed := time.Since(started); elapsed > 5*time.Second {
+			t.Errorf("stream took %s to stop after cancellation", elapsed)
+		}
+		checkFailure(t, events, result, types.StopReasonAborted)
+	})
+}
+
+func conversation() types.Context {
+	return types.Context{
+		SystemPrompt: "You are a conformance test.",
+		Messages: []types.Message{
+			types.UserMessage{
+				Timestamp: time.Now(),
+				Contents:  []types.Content{types.TextContent{Text: "What's the weather in Tokyo?"}},
+			},
+		},
+		Tools: []types.Tool{
+			{
+				Name:        "getWeather",
+				Description: "Get the weather for a given location",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"location": map[string]any{"type": "string"},
+						"days":     map[string]any{"type": "number"},
+					},
+					"required": []string{"location"},
+				},
+			},
+		},
+	}
+}
+
+// collect drains a stream following the documented consumption order
+func collect(t *testing.T, stream types.AssistantMessageEventStream) ([]types.AssistantMessageEvent, types.AssistantMessage, error) {
+	t.Helper()
+
+	done := make(chan struct{})
+	var events []types.AssistantMessageEvent
+	var result types.AssistantMessage
+	var err error
+
+	go func() {
+		defer close(done)
+		for event := range stream.Events {
+			events = append(events, event)
+		}
+		result = <-stream.Result
+		err = <-stream.Err
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(streamTimeout):
+		t.Fatalf("stream did not finish within %s", streamTimeout)
+	}
+	return events, result, err
+}
+
+type block struct {
+	kind   string
+	id     string
+	name   string
+	deltas strings.Builder
+	ended  bool
+}
+
+// checkSequence verifies start→blocks→done ordering, content index
+// assignment and that block contents match their deltas.
+func checkSequence(t *testing.T, events []types.AssistantMessageEvent, result types.AssistantMessage) {
+	t.Helper()
+
+	if len(events) < 2 {
+		t.Fatalf("got %d events, want at least start and done", len(events))
+	}
+	if _, ok := events[0].(types.EventStart); !ok {
+		t.Errorf("first event = %T, want EventStart", events[0])
+	}
+
+	var blocks []*block
+	open := func(index int, kind string, event types.AssistantMessageEvent) *block {
+		if index < 0 || index >= len(blocks) {
+			t.Errorf("%T references unknown content index %d", event, index)
+			return nil
+		}
+		b := blocks[index]
+		if b.kind != kind || b.ended {
+			t.Errorf("%T for content index %d does not match an open %s block", event, index, kind)
+			return nil
+		}
+		return b
+	}
+	start := func(index int, kind string) *block {
+		if index != len(blocks) {
+			t.Errorf("%s block started with content index %d, want %d", kind, index, len(blocks))
+		}
+		b := &block{kind: kind}
+		blocks = append(blocks, b)
+		return b
+	}
+
+	for i, event := range events[1 : len(events)-1] {
+		switch e := event.(type) {
+		case types.EventTextStart:
+			start(e.ContentIndex, "text")
+		case types.EventTextDelta:
+			if b := open(e.ContentIndex, "text", e); b != nil {
+				b.deltas.WriteString(e.Delta)
+			}
+		case types.EventTextEnd:
+			if b := open(e.ContentIndex, "text", e); b != nil {
+				b.ended = true
+				if got := b.deltas.String(); got != e.Content {
+					t.Errorf("EventTextEnd.Content = %q, want concatenated deltas %q", e.Content, got)
+				}
+			}
+		case types.EventToolcallStart:
+			b := start(e.ContentIndex, "toolCall")
+			b.id, b.name = e.ID, e.Name
+		case types.EventToolcallDelta:
+			if b := open(e.ContentIndex, "toolCall", e); b != nil {
+				b.deltas.WriteString(e.Delta)
+				if e.ID != b.id || e.Name != b.name {
+					t.Errorf("EventToolcallDelta ID/name = %q/%q, want %q/%q from start", e.ID, e.Name, b.id, b.name)
+				}
+				if e.Arguments == nil {
+					t.Error("EventToolcallDelta.Arguments is nil")
+				}
+			}
+		case types.EventToolcallEnd:
+			if b := open(e.ContentIndex, "toolCall", e); b != nil {
+				b.ended = true
+				if e.ToolCall.ID != b.id || e.ToolCall.Name != b.name {
+					t.Errorf("EventToolcallStart ID/name = %q/%q, want %q/%q", b.id, b.name, e.ToolCall.ID, e.ToolCall.Name)
+				}
+				checkToolArguments(t, b.deltas.String(), e.ToolCall)
+			}
+		case types.EventStart, types.EventDone, types.EventError:
+			t.Errorf("event %d: unexpected %T in the middle of the stream", i+1, e)
+		}
+	}
+
+	done, ok := events[len(events)-1].(types.EventDone)
+	if !ok {
+		t.Fatalf("last event = %T, want EventDone", events[len(events)-1])
+	}
+	for i, b := range blocks {
+		if !b.ended {
+			t.Errorf("%s block %d was never ended", b.kind, i)
+		}
+	}
+	if done.Reason != result.StopReason {
+		t.Errorf("EventDone.Reason = %q, want result stop reason %q", done.Reason, result.StopReason)
+	}
+	if !equalJSON(done.Message, result) {
+		t.Errorf("EventDone.Message differs from the stream result")
+	}
+	if len(result.Contents) != len(blocks) {
+		t.Fatalf("result has %d contents, want one per block (%d)", len(result.Contents), len(blocks))
+	}
+	for i, content := range result.Contents {
+		if content.Type() != blocks[i].kind {
+			t.Errorf("result content %d is %s, want %s to match its content index", i, content.Type(), blocks[i].kind)
+		}
+	}
+}
+
+func checkToolArguments(t *testing.T, raw string, call types.ToolCall) {
+	t.Helper()
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		t.Errorf("tool call %s deltas are not valid JSON: %q", call.Name, raw)
+		return
+	}
+	if !equalJSON(args, call.Arguments) {
+		t.Errorf("tool call %s arguments = %v, want parsed deltas %v", call.Name, call.Arguments, args)
+	}
+}
+
+func checkFailure(t *testing.T, events []types.AssistantMessageEvent, result types.AssistantMessage, reason types.StopReason) {
+	t.Helper()
+
+	if len(events) == 0 {
+		t.Fatal("got no events, want EventError")
+	}
+	last, ok := events[len(events)-1].(types.EventError)
+	if !ok {
+		t.Fatalf("last event = %T, want EventError", events[len(events)-1])
+	}
+	if last.Reason != reason {
+		t.Errorf("EventError.Reason = %q, want %q", last.Reason, reason)
+	}
+	for _, event := range events[:len(events)-1] {
+		switch event.(type) {
+		case types.EventDone, types.EventError:
+			t.Errorf("unexpected %T before the final EventError", event)
+		}
+	}
+	if result.StopReason != reason {
+		t.Errorf("result stop reason = %q, want %q", result.StopReason, reason)
+	}
+	if result.ErrorMessage == nil {
+		t.Error("result has no ErrorMessage")
+	}
+}
+
+func checkStopReason(t *testing.T, result types.AssistantMessage, want types.StopReason) {
+	t.Helper()
+
+	if result.StopReason != want {
+		t.Errorf("stop reason = %q, want %q", result.StopReason, want)
+	}
+}
+
+func checkContents(t *testing.T, result types.AssistantMessage, want ...types.Content) {
+	t.Helper()
+
+	if len(result.Contents) != len(want) {
+		t.Fatalf("got %d contents %v, want %d", len(result.Contents), result.Contents, len(want))
+	}
+	for i := range want {
+		got := result.Contents[i]
+		// RawArguments is provider-specific formatting; compare parsed arguments only
+		if call, ok := got.(types.ToolCall); ok {
+			call.RawArguments = ""
+			got = call
+		}
+		if !equalJSON(got, want[i]) {
+			t.Errorf("content %d = %+v, want %+v", i, result.Contents[i], want[i])
+		}
+	}
+}
+
+func equalJSON(a, b any) bool {
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aj) == string(bj)
+}
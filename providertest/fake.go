@@ -0,0 +1,325 @@
+package providertest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/partialjson"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// ErrNoResponses is returned when the fake is called more often than scripted
+var ErrNoResponses = errors.New("providertest: no scripted responses left")
+
+// defaultChunkSize is the number of runes per streamed delta
+const defaultChunkSize = 4
+
+// Response scripts the outcome of one call to a Fake
+type Response struct {
+	Contents []types.Content
+	// StopReason defaults to tool_use when Contents has tool calls and stop otherwise
+	StopReason types.StopReason
+	Usage      types.Usage
+	// Err fails the call before any content is produced
+	Err error
+	// ChunkSize is the number of runes per streamed delta
+	ChunkSize int
+	// Delay is slept before every streamed delta
+	Delay time.Duration
+	// FailAfter makes the stream fail with FailErr after that many deltas
+	FailAfter int
+	FailErr   error
+}
+
+// Text scripts a plain text answer
+func Text(text string) Response {
+	return Response{
+		Contents: []types.Content{types.TextContent{Text: text}},
+	}
+}
+
+// ToolCalls scripts an answer that calls the given tools
+func ToolCalls(calls ...types.ToolCall) Response {
+	contents := make([]types.Content, 0, len(calls))
+	for _, call := range calls {
+		contents = append(contents, call)
+	}
+	return Response{
+		Contents: contents,
+	}
+}
+
+// Error scripts a call that fails immediately with err
+func Error(err error) Response {
+	return Response{
+		Err: err,
+	}
+}
+
+// WithDelay returns r with a pause before every streamed delta
+func (r Response) WithDelay(d time.Duration) Response {
+	r.Delay = d
+	return r
+}
+
+// WithChunkSize returns r streamed in deltas of n runes
+func (r Response) WithChunkSize(n int) Response {
+	r.ChunkSize = n
+	return r
+}
+
+// WithUsage returns r reporting the given token usage
+func (r Response) WithUsage(usage types.Usage) Response {
+	r.Usage = usage
+	return r
+}
+
+// FailingAfter returns r failing with err after n streamed deltas
+func (r Response) FailingAfter(n int, err error) Response {
+	r.FailAfter = n
+	r.FailErr = err
+	return r
+}
+
+// Fake is a scriptable provider.Provider that records every context it receives
+type Fake struct {
+	providerType types.ModelProvider
+	modelID      string
+	responses    []Response
+	calls        []types.Context
+	mu           sync.Mutex
+}
+
+// New creates a fake that answers calls with responses, in order
+func New(providerType types.ModelProvider, modelID string, responses ...Response) *Fake {
+	return &Fake{
+		providerType: providerType,
+		modelID:      modelID,
+		responses:    responses,
+	}
+}
+
+func (f *Fake) Model() string {
+	return f.modelID
+}
+
+func (f *Fake) ProviderType() types.ModelProvider {
+	return f.providerType
+}
+
+// Push appends responses to the script
+func (f *Fake) Push(responses ...Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.responses = append(f.responses, responses...)
+}
+
+// Calls returns the contexts received so far, in call order
+func (f *Fake) Calls() []types.Context {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]types.Context, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// Remaining returns the number of scripted responses not yet consumed
+func (f *Fake) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.responses)
+}
+
+func (f *Fake) next(conversation types.Context) (Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, conversation)
+	if len(f.responses) == 0 {
+		return Response{}, ErrNoResponses
+	}
+
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+	return response, nil
+}
+
+func (f *Fake) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	response, err := f.next(conversation)
+	if err != nil {
+		return types.AssistantMessage{}, err
+	}
+	if response.Err != nil {
+		return types.AssistantMessage{}, response.Err
+	}
+	if response.FailErr != nil {
+		return types.AssistantMessage{}, response.FailErr
+	}
+	if err := ctx.Err(); err != nil {
+		return types.AssistantMessage{}, err
+	}
+
+	return f.message(response), nil
+}
+
+func (f *Fake) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
+	stream := types.NewAssistantMessageEventStream()
+	response, scriptErr := f.next(conversation)
+
+	go func() {
+		final := f.message(response)
+		output := final
+		output.Contents = []types.Content{}
+		output.StopReason = ""
+		output.Usage = types.Usage{}
+
+		finish := func(err error) {
+			if err != nil {
+				output.StopReason = types.StopReasonError
+				if ctx.Err() != nil {
+					output.StopReason = types.StopReasonAborted
+				}
+				errMsg := err.Error()
+				output.ErrorMessage = &errMsg
+				stream.Events <- types.EventError{Reason: output.StopReason, Error: output}
+			} else {
+				output = final
+				stream.Events <- types.EventDone{Reason: output.StopReason, Message: output}
+			}
+			stream.Result <- output
+			stream.Err <- err
+			stream.Close()
+		}
+
+		if scriptErr != nil {
+			finish(scriptErr)
+			return
+		}
+		if response.Err != nil {
+			finish(response.Err)
+			return
+		}
+
+		stream.Events <- types.EventStart{}
+
+		sent := 0
+		// emit sleeps and counts one delta, returning an error if the stream must stop
+		emit := func(event types.AssistantMessageEvent) error {
+			if response.FailErr != nil && sent >= response.FailAfter {
+				return response.FailErr
+			}
+			if response.Delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(response.Delay):
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			stream.Events <- event
+			sent++
+			return nil
+		}
+
+		for i, content := range final.Contents {
+			switch c := content.(type) {
+			case types.TextContent:
+				stream.Events <- types.EventTextStart{ContentIndex: i, Partial: output}
+				for _, delta := range chunk(c.Text, response.ChunkSize) {
+					if err := emit(types.EventTextDelta{ContentIndex: i, Delta: delta, Partial: output}); err != nil {
+						finish(err)
+						return
+					}
+				}
+				stream.Events <- types.EventTextEnd{ContentIndex: i, Content: c.Text, Partial: output}
+
+			case types.ToolCall:
+				args, _ := json.Marshal(c.Arguments)
+				stream.Events <- types.EventToolcallStart{ContentIndex: i, ID: c.ID, Name: c.Name, Partial: output}
+				var received strings.Builder
+				for _, delta := range chunk(string(args), response.ChunkSize) {
+					received.WriteString(delta)
+					event := types.EventToolcallDelta{
+						ContentIndex: i,
+						ID:           c.ID,
+						Name:         c.Name,
+						Delta:        delta,
+						Arguments:    partialjson.ParseObject(received.String()),
+						Partial:      output,
+					}
+					if err := emit(event); err != nil {
+						finish(err)
+						return
+					}
+				}
+				stream.Events <- types.EventToolcallEnd{ContentIndex: i, ToolCall: c, Partial: output}
+
+			case types.AudioContent:
+				stream.Events <- types.EventAudioStart{ContentIndex: i, Partial: output}
+				if err := emit(types.EventAudioDelta{ContentIndex: i, Data: c.Data, Transcript: c.Transcript, Partial: output}); err != nil {
+					finish(err)
+					return
+				}
+				stream.Events <- types.EventAudioEnd{ContentIndex: i, Content: c, Partial: output}
+			}
+
+			output.Contents = append(output.Contents, content)
+		}
+
+		if response.FailErr != nil && sent >= response.FailAfter {
+			finish(response.FailErr)
+			return
+		}
+		finish(nil)
+	}()
+
+	return stream
+}
+
+// message builds the final assistant message for a scripted response
+func (f *Fake) message(response Response) types.AssistantMessage {
+	stopReason := response.StopReason
+	if stopReason == "" {
+		stopReason = types.StopReasonStop
+		for _, content := range response.Contents {
+			if _, ok := content.(types.ToolCall); ok {
+				stopReason = types.StopReasonToolUse
+				break
+			}
+		}
+	}
+
+	contents := make([]types.Content, len(response.Contents))
+	copy(contents, response.Contents)
+
+	return types.AssistantMessage{
+		Contents:   contents,
+		Timestamp:  time.Now(),
+		Provider:   f.providerType,
+		StopReason: stopReason,
+		Usage:      response.Usage,
+	}
+}
+
+func chunk(s string, size int) []string {
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+
+	runes := []rune(s)
+	chunks := make([]string, 0, len(runes)/size+1)
+	for start := 0; start < len(runes); start += size {
+		end := min(start+size, len(runes))
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
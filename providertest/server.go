@@ -0,0 +1,284 @@
+package providertest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// HTTPError scripts an API error response with the given status code
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+// MockServer is a local OpenAI-compatible chat completions endpoint that
+// answers with scripted responses. Response.Err is returned as an HTTP error
+// (status 500 unless it is an *HTTPError) and Response.FailErr as an
+// in-stream error event.
+type MockServer struct {
+	server    *httptest.Server
+	responses []Response
+	requests  [][]byte
+	mu        sync.Mutex
+}
+
+// NewMockServer starts a mock server that is closed when the test finishes
+func NewMockServer(t testing.TB) *MockServer {
+	s := &MockServer{}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the base URL to configure providers with
+func (s *MockServer) URL() string {
+	return s.server.URL + "/v1"
+}
+
+// Push appends responses to the script
+func (s *MockServer) Push(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses = append(s.responses, responses...)
+}
+
+// Requests returns the raw request bodies received so far
+func (s *MockServer) Requests() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([][]byte, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+type mockRequest struct {
+	Stream        bool `json:"stream"`
+	StreamOptions struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+}
+
+func (s *MockServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, body)
+	var response Response
+	if len(s.responses) == 0 {
+		response = Error(&HTTPError{StatusCode: http.StatusBadRequest, Message: ErrNoResponses.Error()})
+	} else {
+		response = s.responses[0]
+		s.responses = s.responses[1:]
+	}
+	s.mu.Unlock()
+
+	var req mockRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	if response.Err != nil {
+		writeError(w, response.Err)
+		return
+	}
+
+	if !req.Stream {
+		if response.FailErr != nil {
+			writeError(w, response.FailErr)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(completionBody(response))
+		return
+	}
+
+	s.stream(w, r, response, req.StreamOptions.IncludeUsage)
+}
+
+func (s *MockServer) stream(w http.ResponseWriter, r *http.Request, response Response, includeUsage bool) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	send := func(v any) {
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	deltas := 0
+	// sendDelta paces and counts deltas, reporting false once the stream must stop
+	sendDelta := func(delta map[string]any) bool {
+		if response.FailErr != nil && deltas >= response.FailAfter {
+			send(map[string]any{"error": map[string]any{"message": response.FailErr.Error()}})
+			return false
+		}
+		if response.Delay > 0 {
+			select {
+			case <-r.Context().Done():
+				return false
+			case <-time.After(response.Delay):
+			}
+		}
+		send(chunkBody([]map[string]any{{"index": 0, "delta": delta}}, nil))
+		deltas++
+		return true
+	}
+
+	toolIndex := 0
+	for _, content := range response.Contents {
+		switch c := content.(type) {
+		case types.TextContent:
+			for _, part := range chunk(c.Text, response.ChunkSize) {
+				if !sendDelta(map[string]any{"content": part}) {
+					return
+				}
+			}
+
+		case types.ToolCall:
+			args, _ := json.Marshal(c.Arguments)
+			for i, part := range chunk(string(args), response.ChunkSize) {
+				call := map[string]any{
+					"index":    toolIndex,
+					"function": map[string]any{"arguments": part},
+				}
+				if i == 0 {
+					call["id"] = c.ID
+					call["type"] = "function"
+					call["function"] = map[string]any{"name": c.Name, "arguments": part}
+				}
+				if !sendDelta(map[string]any{"tool_calls": []any{call}}) {
+					return
+				}
+			}
+			toolIndex++
+		}
+	}
+
+	if response.FailErr != nil {
+		send(map[string]any{"error": map[string]any{"message": response.FailErr.Error()}})
+		return
+	}
+
+	send(chunkBody([]map[string]any{{"index": 0, "delta": map[string]any{}, "finish_reason": finishReason(response)}}, nil))
+	if includeUsage {
+		send(chunkBody([]map[string]any{}, usageBody(response.Usage)))
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func chunkBody(choices []map[string]any, usage map[string]any) map[string]any {
+	body := map[string]any{
+		"id":      "chatcmpl-mock",
+		"object":  "chat.completion.chunk",
+		"created": time.Now().Unix(),
+		"model":   "mock",
+		"choices": choices,
+	}
+	if usage != nil {
+		body["usage"] = usage
+	}
+	return body
+}
+
+func completionBody(response Response) map[string]any {
+	message := map[string]any{"role": "assistant", "content": ""}
+	text := ""
+	toolCalls := []any{}
+
+	for _, content := range response.Contents {
+		switch c := content.(type) {
+		case types.TextContent:
+			text += c.Text
+		case types.ToolCall:
+			args, _ := json.Marshal(c.Arguments)
+			toolCalls = append(toolCalls, map[string]any{
+				"id":       c.ID,
+				"type":     "function",
+				"function": map[string]any{"name": c.Name, "arguments": string(args)},
+			})
+		}
+	}
+	message["content"] = text
+	if len(toolCalls) > 0 {
+		message["tool_calls"] = toolCalls
+	}
+
+	return map[string]any{
+		"id":      "chatcmpl-mock",
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   "mock",
+		"choices": []any{map[string]any{
+			"index":         0,
+			"message":       message,
+			"finish_reason": finishReason(response),
+		}},
+		"usage": usageBody(response.Usage),
+	}
+}
+
+func usageBody(usage types.Usage) map[string]any {
+	return map[string]any{
+		"prompt_tokens":     usage.InputTokens,
+		"completion_tokens": usage.OutputTokens,
+		"total_tokens":      usage.TotalTokens,
+	}
+}
+
+func finishReason(response Response) string {
+	switch response.StopReason {
+	case types.StopReasonLength:
+		return "length"
+	case types.StopReasonToolUse:
+		return "tool_calls"
+	case types.StopReasonStop:
+		return "stop"
+	}
+
+	for _, content := range response.Contents {
+		if _, ok := content.(types.ToolCall); ok {
+			return "tool_calls"
+		}
+	}
+	return "stop"
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.StatusCode
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"message": err.Error(),
+			"type":    "mock_error",
+		},
+	})
+}
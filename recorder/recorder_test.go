@@ -0,0 +1,153 @@
+package recorder_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/internal/provider/openai"
+	"github.com/rahulSailesh-shah/go-pi-ai/recorder"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+const fixture = "testdata/openai_chat.json"
+
+func question(text string) types.Context {
+	return types.Context{
+		SystemPrompt: "Answer in one sentence.",
+		Messages: []types.Message{
+			types.UserMessage{Contents: []types.Content{types.TextContent{Text: text}}},
+		},
+	}
+}
+
+func replayProvider(t *testing.T) (*openai.Provider, *recorder.Recorder) {
+	t.Helper()
+
+	rec, err := recorder.New(fixture, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The host is never contacted in replay mode
+	config := openai.Config{URL: "http://replay.invalid/v1", APIKey: "test", HTTPClient: rec.Client()}
+	return openai.New(config, "gpt-4o-mini", types.ProviderOpenAI), rec
+}
+
+func TestReplayStream(t *testing.T) {
+	p, _ := replayProvider(t)
+
+	stream := p.Stream(context.Background(), question("What is the capital of France?"))
+	var deltas []string
+	for event := range stream.Events {
+		if delta, ok := event.(types.EventTextDelta); ok {
+			deltas = append(deltas, delta.Delta)
+		}
+	}
+	result := <-stream.Result
+	if err := <-stream.Err; err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	if got := strings.Join(deltas, ""); got != "Paris is the capital of France." {
+		t.Errorf("deltas = %q", got)
+	}
+	if result.StopReason != types.StopReasonStop {
+		t.Errorf("stop reason = %s, want stop", result.StopReason)
+	}
+	if result.Usage.TotalTokens != 21 {
+		t.Errorf("total tokens = %d, want 21", result.Usage.TotalTokens)
+	}
+}
+
+func TestReplayComplete(t *testing.T) {
+	p, _ := replayProvider(t)
+
+	message, err := p.Complete(context.Background(), question("What is the capital of France?"))
+	if err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if len(message.Contents) != 1 || message.Contents[0] != (types.TextContent{Text: "Paris is the capital of France."}) {
+		t.Errorf("contents = %#v", message.Contents)
+	}
+}
+
+func TestReplayNoMatch(t *testing.T) {
+	p, _ := replayProvider(t)
+
+	_, err := p.Complete(context.Background(), question("What is the capital of Spain?"))
+	if !errors.Is(err, recorder.ErrNoInteraction) {
+		t.Fatalf("err = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestReplayUsesInteractionOnce(t *testing.T) {
+	p, rec := replayProvider(t)
+
+	conversation := question("What is the capital of France?")
+	if _, err := p.Complete(context.Background(), conversation); err != nil {
+		t.Fatalf("first complete failed: %v", err)
+	}
+
+	// Replaying the same request body directly avoids the SDK's retries
+	body := `{"messages":[{"content":"Answer in one sentence.","role":"system"},{"content":[{"text":"What is the capital of France?","type":"text"}],"role":"user"}],"model":"gpt-4o-mini","seed":0,"tools":[]}`
+	req, _ := http.NewRequest(http.MethodPost, "http://replay.invalid/v1/chat/completions", strings.NewReader(body))
+	if _, err := rec.RoundTrip(req); !errors.Is(err, recorder.ErrNoInteraction) {
+		t.Fatalf("second replay err = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprint(w, `{"id":"c","object":"chat.completion","created":1,"model":"m","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"recorded"}}]}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := recorder.New(path, recorder.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeRecord {
+		t.Fatalf("mode = %v, want record for a missing fixture", rec.Mode())
+	}
+
+	conversation := question("hi")
+	p := openai.New(openai.Config{URL: server.URL, APIKey: "test", HTTPClient: rec.Client()}, "m", types.ProviderOpenAI)
+	if _, err := p.Complete(context.Background(), conversation); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "session=secret") {
+		t.Error("fixture kept the Set-Cookie header")
+	}
+
+	rec, err = recorder.New(path, recorder.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeReplay {
+		t.Fatalf("mode = %v, want replay for an existing fixture", rec.Mode())
+	}
+
+	server.Close()
+	p = openai.New(openai.Config{URL: "http://replay.invalid", APIKey: "test", HTTPClient: rec.Client()}, "m", types.ProviderOpenAI)
+	message, err := p.Complete(context.Background(), conversation)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if message.Contents[0] != (types.TextContent{Text: "recorded"}) {
+		t.Errorf("contents = %#v", message.Contents)
+	}
+}
@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/provider"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+// Attribute keys following the OpenTelemetry GenAI semantic conventions
+const (
+	AttrOperationName        = "gen_ai.operation.name"
+	AttrSystem               = "gen_ai.system"
+	AttrRequestModel         = "gen_ai.request.model"
+	AttrResponseFinishReason = "gen_ai.response.finish_reasons"
+	AttrUsageInputTokens     = "gen_ai.usage.input_tokens"
+	AttrUsageOutputTokens    = "gen_ai.usage.output_tokens"
+	AttrToolName             = "gen_ai.tool.name"
+	AttrToolCallID           = "gen_ai.tool.call.id"
+	AttrTimeToFirstToken     = "gen_ai.response.time_to_first_token"
+	AttrStreaming            = "gen_ai.request.streaming"
+	AttrErrorType            = "error.type"
+)
+
+const (
+	OperationChat        = "chat"
+	OperationExecuteTool = "execute_tool"
+)
+
+// Middleware returns a provider middleware that creates one span per
+// Complete/Stream call, parented to the span carried by the call context.
+func Middleware(tracer *Tracer) provider.Middleware {
+	return func(next provider.Provider) provider.Provider {
+		return &tracedProvider{
+			next:   next,
+			tracer: tracer,
+		}
+	}
+}
+
+type tracedProvider struct {
+	next   provider.Provider
+	tracer *Tracer
+}
+
+func (p *tracedProvider) Unwrap() provider.Provider {
+	return p.next
+}
+
+func (p *tracedProvider) Model() string {
+	return p.next.Model()
+}
+
+func (p *tracedProvider) ProviderType() types.ModelProvider {
+	return p.next.ProviderType()
+}
+
+func (p *tracedProvider) Complete(ctx context.Context, conversation types.Context) (types.AssistantMessage, error) {
+	ctx, span := p.startSpan(ctx, false)
+	defer span.End()
+
+	message, err := p.next.Complete(ctx, conversation)
+	endModelSpan(span, message, err)
+	return message, err
+}
+
+func (p *tracedProvider) Stream(ctx context.Context, conversation types.Context) types.AssistantMessageEventStream {
+	ctx, span := p.startSpan(ctx, true)
+	started := time.Now()
+	firstToken := false
+
+	return provider.InterceptStream(
+		p.next.Stream(ctx, conversation),
+		func(event types.AssistantMessageEvent) {
+			if firstToken {
+				return
+			}
+			switch event.(type) {
+			case types.EventTextDelta, types.EventToolcallDelta, types.EventAudioDelta:
+				firstToken = true
+				span.SetAttribute(AttrTimeToFirstToken, time.Since(started).Seconds())
+				span.AddEvent("first_token", nil)
+			}
+		},
+		func(message types.AssistantMessage, err error) {
+			endModelSpan(span, message, err)
+			span.End()
+		},
+	)
+}
+
+func (p *tracedProvider) startSpan(ctx context.Context, streaming bool) (context.Context, *Span) {
+	ctx, span := p.tracer.Start(ctx, OperationChat+" "+p.next.Model())
+	span.SetAttribute(AttrOperationName, OperationChat)
+	span.SetAttribute(AttrSystem, string(p.next.ProviderType()))
+	span.SetAttribute(AttrRequestModel, p.next.Model())
+	span.SetAttribute(AttrStreaming, streaming)
+	return ctx, span
+}
+
+func endModelSpan(span *Span, message types.AssistantMessage, err error) {
+	if err != nil {
+		span.SetAttribute(AttrErrorType, errorType(message))
+		span.RecordError(err)
+		return
+	}
+
+	span.SetAttribute(AttrResponseFinishReason, []string{string(message.StopReason)})
+	span.SetAttribute(AttrUsageInputTokens, message.Usage.InputTokens)
+	span.SetAttribute(AttrUsageOutputTokens, message.Usage.OutputTokens)
+	span.SetStatus(StatusOK, "")
+}
+
+func errorType(message types.AssistantMessage) string {
+	if message.StopReason == "" {
+		return string(types.StopReasonError)
+	}
+	return string(message.StopReason)
+}
+
+// TraceTool runs fn inside an "execute_tool" span that is a child of the
+// span in ctx, typically the agent loop's span.
+func (t *Tracer) TraceTool(
+	ctx context.Context,
+	call types.ToolCall,
+	fn func(ctx context.Context) (types.ToolMessage, error),
+) (types.ToolMessage, error) {
+	ctx, span := t.Start(ctx, OperationExecuteTool+" "+call.Name)
+	defer span.End()
+
+	span.SetAttribute(AttrOperationName, OperationExecuteTool)
+	span.SetAttribute(AttrToolName, call.Name)
+	span.SetAttribute(AttrToolCallID, call.ID)
+
+	result, err := fn(ctx)
+	switch {
+	case err != nil:
+		span.RecordError(err)
+	case result.IsError:
+		span.SetStatus(StatusError, "tool returned an error result")
+	default:
+		span.SetStatus(StatusOK, "")
+	}
+	return result, err
+}
@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"sync"
+	"time"
+)
+
+// StatusCode is the outcome of a span
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Event is a timestamped annotation on a span
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]any
+}
+
+// SpanData is the immutable record of a finished span handed to exporters
+type SpanData struct {
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Name          string
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]any
+	Events        []Event
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Duration returns the time between span start and end
+func (d SpanData) Duration() time.Duration {
+	return d.EndTime.Sub(d.StartTime)
+}
+
+// Span is an in-progress unit of work. All methods are safe on a nil span.
+type Span struct {
+	data   SpanData
+	tracer *Tracer
+	ended  bool
+	mu     sync.Mutex
+}
+
+// TraceID returns the ID shared by all spans of a trace
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.data.TraceID
+}
+
+// SpanID returns the ID of this span
+func (s *Span) SpanID() string {
+	if s == nil {
+		return ""
+	}
+	return s.data.SpanID
+}
+
+// SetAttribute records a key/value attribute on the span
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.data.Attributes[key] = value
+}
+
+// AddEvent records a named event at the current time
+func (s *Span) AddEvent(name string, attributes map[string]any) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.data.Events = append(s.data.Events, Event{
+		Name:       name,
+		Time:       time.Now(),
+		Attributes: attributes,
+	})
+}
+
+// SetStatus sets the span outcome
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.data.Status = code
+	s.data.StatusMessage = message
+}
+
+// RecordError records err as an exception event and marks the span failed
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.AddEvent("exception", map[string]any{
+		"exception.message": err.Error(),
+	})
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and exports it. Calls after the first are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.export(data)
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Exporter receives finished spans
+type Exporter interface {
+	ExportSpan(ctx context.Context, span SpanData) error
+}
+
+// ExporterFunc adapts a function to the Exporter interface
+type ExporterFunc func(ctx context.Context, span SpanData) error
+
+func (f ExporterFunc) ExportSpan(ctx context.Context, span SpanData) error {
+	return f(ctx, span)
+}
+
+// Tracer creates spans and hands them to an exporter when they end
+type Tracer struct {
+	exporter Exporter
+	onError  func(error)
+}
+
+// TracerOption configures a Tracer
+type TracerOption func(*Tracer)
+
+// WithErrorHandler is called when the exporter fails to export a span
+func WithErrorHandler(fn func(error)) TracerOption {
+	return func(t *Tracer) {
+		t.onError = fn
+	}
+}
+
+// NewTracer creates a tracer that exports to exporter
+func NewTracer(exporter Exporter, opts ...TracerOption) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+type spanContextKey struct{}
+
+// Start creates a span that is a child of the span in ctx, if any, and
+// returns a context carrying the new span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			SpanID:     newID(8),
+			Name:       name,
+			StartTime:  time.Now(),
+			Attributes: make(map[string]any),
+		},
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.data.TraceID = parent.TraceID()
+		span.data.ParentSpanID = parent.SpanID()
+	} else {
+		span.data.TraceID = newID(16)
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// SpanFromContext returns the current span, or nil if there is none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+func (t *Tracer) export(data SpanData) {
+	if t == nil || t.exporter == nil {
+		return
+	}
+
+	if err := t.exporter.ExportSpan(context.Background(), data); err != nil && t.onError != nil {
+		t.onError(err)
+	}
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// InMemoryExporter keeps exported spans in memory, intended for tests
+type InMemoryExporter struct {
+	spans []SpanData
+	mu    sync.Mutex
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(ctx context.Context, span SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+// Spans returns the spans exported so far in the order they ended
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	spans := make([]SpanData, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// Reset drops all recorded spans
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
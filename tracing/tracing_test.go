@@ -0,0 +1,157 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rahulSailesh-shah/go-pi-ai/providertest"
+	"github.com/rahulSailesh-shah/go-pi-ai/tracing"
+	"github.com/rahulSailesh-shah/go-pi-ai/types"
+)
+
+func traced(responses ...providertest.Response) (*tracing.Tracer, *tracing.InMemoryExporter, *providertest.Fake) {
+	exporter := tracing.NewInMemoryExporter()
+	fake := providertest.New(types.ProviderOpenAI, "gpt-4o", responses...)
+	return tracing.NewTracer(exporter), exporter, fake
+}
+
+func drain(stream types.AssistantMessageEventStream) (types.AssistantMessage, error) {
+	for range stream.Events {
+	}
+	return <-stream.Result, <-stream.Err
+}
+
+func spanNamed(t *testing.T, spans []tracing.SpanData, name string) tracing.SpanData {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("no span %q in %+v", name, spans)
+	return tracing.SpanData{}
+}
+
+func TestSpanHierarchy(t *testing.T) {
+	tracer, exporter, fake := traced(providertest.Text("Sunny").WithUsage(types.Usage{InputTokens: 12, OutputTokens: 3}))
+	p := tracing.Middleware(tracer)(fake)
+
+	ctx, root := tracer.Start(context.Background(), "agent")
+	if _, err := p.Complete(ctx, types.Context{}); err != nil {
+		t.Fatal(err)
+	}
+	var toolSpanID string
+	call := types.ToolCall{ID: "call_1", Name: "get_weather"}
+	_, err := tracer.TraceTool(ctx, call, func(ctx context.Context) (types.ToolMessage, error) {
+		toolSpanID = tracing.SpanFromContext(ctx).SpanID()
+		return types.ToolMessage{ToolCallId: call.ID}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	agent := spanNamed(t, spans, "agent")
+	chat := spanNamed(t, spans, "chat gpt-4o")
+	tool := spanNamed(t, spans, "execute_tool get_weather")
+
+	if agent.ParentSpanID != "" {
+		t.Errorf("root span has parent %s", agent.ParentSpanID)
+	}
+	for _, child := range []tracing.SpanData{chat, tool} {
+		if child.TraceID != agent.TraceID || child.ParentSpanID != agent.SpanID {
+			t.Errorf("%s: trace %s parent %s, want trace %s parent %s", child.Name, child.TraceID, child.ParentSpanID, agent.TraceID, agent.SpanID)
+		}
+	}
+	if tool.SpanID != toolSpanID {
+		t.Errorf("tool function ran in span %s, want %s", toolSpanID, tool.SpanID)
+	}
+
+	if chat.Status != tracing.StatusOK {
+		t.Errorf("chat status = %v, want ok", chat.Status)
+	}
+	for key, want := range map[string]any{
+		tracing.AttrOperationName:     tracing.OperationChat,
+		tracing.AttrSystem:            "openai",
+		tracing.AttrRequestModel:      "gpt-4o",
+		tracing.AttrStreaming:         false,
+		tracing.AttrUsageInputTokens:  12,
+		tracing.AttrUsageOutputTokens: 3,
+	} {
+		if got := chat.Attributes[key]; got != want {
+			t.Errorf("chat %s = %v, want %v", key, got, want)
+		}
+	}
+	if got := tool.Attributes[tracing.AttrToolCallID]; got != "call_1" {
+		t.Errorf("tool call id = %v", got)
+	}
+}
+
+func TestStreamTimeToFirstToken(t *testing.T) {
+	delay := 5 * time.Millisecond
+	tracer, exporter, fake := traced(providertest.Text("Hello there").WithChunkSize(3).WithDelay(delay))
+
+	if _, err := drain(tracing.Middleware(tracer)(fake).Stream(context.Background(), types.Context{})); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	ttft, ok := span.Attributes[tracing.AttrTimeToFirstToken].(float64)
+	if !ok || ttft < delay.Seconds() || ttft > span.Duration().Seconds() {
+		t.Errorf("time to first token = %v, want between %v and the span duration %v", span.Attributes[tracing.AttrTimeToFirstToken], delay, span.Duration())
+	}
+	firstTokens := 0
+	for _, event := range span.Events {
+		if event.Name == "first_token" {
+			firstTokens++
+		}
+	}
+	if firstTokens != 1 {
+		t.Errorf("recorded %d first_token events, want 1", firstTokens)
+	}
+	if span.Attributes[tracing.AttrStreaming] != true || span.Status != tracing.StatusOK {
+		t.Errorf("span = %+v, want a successful streaming span", span)
+	}
+}
+
+func TestFailedCallsSetErrorStatus(t *testing.T) {
+	failure := errors.New("connection reset")
+	tracer, exporter, fake := traced(
+		providertest.Text("Hello there").WithChunkSize(3).FailingAfter(1, failure),
+		providertest.Error(failure),
+	)
+	p := tracing.Middleware(tracer)(fake)
+
+	if _, err := drain(p.Stream(context.Background(), types.Context{})); !errors.Is(err, failure) {
+		t.Fatalf("Stream error = %v, want %v", err, failure)
+	}
+	if _, err := p.Complete(context.Background(), types.Context{}); !errors.Is(err, failure) {
+		t.Fatalf("Complete error = %v, want %v", err, failure)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	for _, span := range spans {
+		if span.Status != tracing.StatusError || span.StatusMessage != failure.Error() {
+			t.Errorf("status = %v %q, want error %q", span.Status, span.StatusMessage, failure)
+		}
+		if span.Attributes[tracing.AttrErrorType] != string(types.StopReasonError) {
+			t.Errorf("error type = %v", span.Attributes[tracing.AttrErrorType])
+		}
+		if len(span.Events) == 0 || span.Events[len(span.Events)-1].Name != "exception" {
+			t.Errorf("events = %+v, want an exception event", span.Events)
+		}
+	}
+}
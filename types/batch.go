@@ -0,0 +1,55 @@
+package types
+
+import "time"
+
+// BatchRequest is one conversation submitted in a batch, identified by CustomID
+type BatchRequest struct {
+	CustomID string
+	Context  Context
+}
+
+// BatchStatus is the lifecycle state of a batch job
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// Terminal reports whether the job will not change state again
+func (s BatchStatus) Terminal() bool {
+	switch s {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// BatchJob describes a submitted batch
+type BatchJob struct {
+	ID           string
+	Status       BatchStatus
+	InputFileID  string
+	OutputFileID string
+	ErrorFileID  string
+	Total        int
+	Completed    int
+	Failed       int
+	// Errors holds job level failures, e.g. validation errors of the input file
+	Errors    []string
+	CreatedAt time.Time
+}
+
+// BatchResult is the outcome of one request in a batch. Error is set when the
+// request failed, in which case Message is empty.
+type BatchResult struct {
+	CustomID string
+	Message  AssistantMessage
+	Error    string
+}
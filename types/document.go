@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentContent represents an attached file such as a PDF or text document.
+// Data holds the base64 encoded file bytes.
+type DocumentContent struct {
+	Data     string
+	MimeType string
+	Filename string
+}
+
+func (d DocumentContent) Type() string {
+	return "document"
+}
+
+func (d DocumentContent) isContent() {}
+
+// textDocumentTypes lists non text/* MIME types whose contents are plain text
+var textDocumentTypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/yaml":       true,
+	"application/x-yaml":     true,
+	"application/toml":       true,
+	"application/javascript": true,
+	"application/x-sh":       true,
+}
+
+// IsText reports whether the document can be sent to the model as plain text
+func (d DocumentContent) IsText() bool {
+	mimeType, _, _ := mime.ParseMediaType(d.MimeType)
+	return strings.HasPrefix(mimeType, "text/") || textDocumentTypes[mimeType]
+}
+
+// Text returns the decoded contents of a plain text document
+func (d DocumentContent) Text() (string, error) {
+	if !d.IsText() {
+		return "", fmt.Errorf("%w: %s is not a text document", ErrUnsupportedContent, d.MimeType)
+	}
+	data, err := base64.StdEncoding.DecodeString(d.Data)
+	if err != nil {
+		return "", fmt.Errorf("decode document: %w", err)
+	}
+	return string(data), nil
+}
+
+// Size returns the decoded size of the document in bytes
+func (d DocumentContent) Size() int {
+	return base64.StdEncoding.DecodedLen(len(d.Data))
+}
+
+// DocumentFromFile reads a file and encodes it as document content
+func DocumentFromFile(filePath string) (DocumentContent, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return DocumentContent{}, fmt.Errorf("read document: %w", err)
+	}
+	return DocumentFromBytes(data, filepath.Base(filePath)), nil
+}
+
+// DocumentFromBytes encodes data as document content, detecting the MIME type
+// from the filename extension and falling back to content sniffing
+func DocumentFromBytes(data []byte, filename string) DocumentContent {
+	mimeType := mime.TypeByExtension(filepath.Ext(filename))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return DocumentContent{
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+		Filename: filename,
+	}
+}
+
+// DocumentFromText creates a plain text document
+func DocumentFromText(text, filename string) DocumentContent {
+	return DocumentContent{
+		Data:     base64.StdEncoding.EncodeToString([]byte(text)),
+		MimeType: "text/plain; charset=utf-8",
+		Filename: filename,
+	}
+}
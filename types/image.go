@@ -0,0 +1,284 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	_ "image/gif"
+)
+
+// ImageDetail controls how much detail the model uses when processing an image
+type ImageDetail string
+
+const (
+	ImageDetailAuto ImageDetail = "auto"
+	ImageDetailLow  ImageDetail = "low"
+	ImageDetailHigh ImageDetail = "high"
+)
+
+// imageOptions holds the settings applied by image constructors
+type imageOptions struct {
+	detail       ImageDetail
+	maxDimension int
+	maxBytes     int
+}
+
+// ImageOption configures an image constructor
+type ImageOption func(*imageOptions)
+
+// WithImageDetail sets the detail level requested from the model
+func WithImageDetail(detail ImageDetail) ImageOption {
+	return func(o *imageOptions) {
+		o.detail = detail
+	}
+}
+
+// WithMaxDimension downscales images whose width or height exceeds px
+func WithMaxDimension(px int) ImageOption {
+	return func(o *imageOptions) {
+		o.maxDimension = px
+	}
+}
+
+// WithMaxBytes re-encodes images larger than n bytes as JPEG, or as PNG when
+// they have transparency, shrinking them until they fit
+func WithMaxBytes(n int) ImageOption {
+	return func(o *imageOptions) {
+		o.maxBytes = n
+	}
+}
+
+// DataURL returns the URL sent to providers: the remote URL, or a base64 data URL
+func (i ImageContent) DataURL() string {
+	if i.URL != "" {
+		return i.URL
+	}
+	if strings.HasPrefix(i.Data, "data:") || strings.HasPrefix(i.Data, "http://") || strings.HasPrefix(i.Data, "https://") {
+		return i.Data
+	}
+	mimeType := i.MimeType
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	return "data:" + mimeType + ";base64," + i.Data
+}
+
+// Size returns the decoded size of inline image data in bytes; remote images report 0
+func (i ImageContent) Size() int {
+	if i.URL != "" || strings.HasPrefix(i.Data, "http://") || strings.HasPrefix(i.Data, "https://") {
+		return 0
+	}
+	data := i.Data
+	if strings.HasPrefix(data, "data:") {
+		if _, encoded, ok := strings.Cut(data, ","); ok {
+			data = encoded
+		}
+	}
+	return base64.StdEncoding.DecodedLen(len(data))
+}
+
+// ImageFromFile reads an image file and encodes it as base64 content
+func ImageFromFile(filePath string, opts ...ImageOption) (ImageContent, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return ImageContent{}, fmt.Errorf("read image: %w", err)
+	}
+	return ImageFromBytes(data, opts...)
+}
+
+// ImageFromBytes sniffs the MIME type of data and encodes it as base64 content,
+// downscaling or re-encoding it if limits are configured
+func ImageFromBytes(data []byte, opts ...ImageOption) (ImageContent, error) {
+	options := applyImageOptions(opts)
+
+	mimeType := sniffImageType(data)
+	if mimeType == "" {
+		return ImageContent{}, fmt.Errorf("%w: image data (detected %s)", ErrUnsupportedContent, http.DetectContentType(data))
+	}
+
+	data, mimeType, err := fitImage(data, mimeType, options)
+	if err != nil {
+		return ImageContent{}, err
+	}
+
+	return ImageContent{
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+		Detail:   options.detail,
+	}, nil
+}
+
+// ImageFromURL references a remote image; the provider fetches it
+func ImageFromURL(url string, opts ...ImageOption) ImageContent {
+	options := applyImageOptions(opts)
+	return ImageContent{
+		URL:      url,
+		MimeType: mime.TypeByExtension(path.Ext(strings.SplitN(url, "?", 2)[0])),
+		Detail:   options.detail,
+	}
+}
+
+func applyImageOptions(opts []ImageOption) imageOptions {
+	var options imageOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func sniffImageType(data []byte) string {
+	// http.DetectContentType does not recognise every format models accept
+	if len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+		return "image/webp"
+	}
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ""
+	}
+	return mimeType
+}
+
+// fitImage applies the dimension and size limits, returning data unchanged when it already fits
+func fitImage(data []byte, mimeType string, options imageOptions) ([]byte, string, error) {
+	if options.maxDimension <= 0 && (options.maxBytes <= 0 || len(data) <= options.maxBytes) {
+		return data, mimeType, nil
+	}
+	// The standard library has no WebP decoder, so WebP images cannot be resized
+	if mimeType == "image/webp" {
+		return nil, "", fmt.Errorf("%w: resizing WebP images; convert to PNG or JPEG first", ErrUnsupportedContent)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+	tooLarge := options.maxDimension > 0 && (config.Width > options.maxDimension || config.Height > options.maxDimension)
+	if !tooLarge && (options.maxBytes <= 0 || len(data) <= options.maxBytes) {
+		return data, mimeType, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image: %w", err)
+	}
+	if tooLarge {
+		img = scaleImage(img, options.maxDimension)
+	}
+
+	// JPEG has no alpha channel; transparent images stay PNG
+	transparent := !isOpaque(img)
+
+	if options.maxBytes <= 0 {
+		var buf bytes.Buffer
+		if mimeType == "image/jpeg" && !transparent {
+			err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		} else {
+			mimeType = "image/png"
+			err = png.Encode(&buf, img)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("encode image: %w", err)
+		}
+		return buf.Bytes(), mimeType, nil
+	}
+
+	// Lower the JPEG quality, then the resolution, until the image fits;
+	// transparent images only lose resolution
+	for {
+		if transparent {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return nil, "", fmt.Errorf("encode image: %w", err)
+			}
+			if buf.Len() <= options.maxBytes {
+				return buf.Bytes(), "image/png", nil
+			}
+		} else {
+			for _, quality := range []int{90, 75, 60, 45} {
+				var buf bytes.Buffer
+				if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+					return nil, "", fmt.Errorf("encode image: %w", err)
+				}
+				if buf.Len() <= options.maxBytes {
+					return buf.Bytes(), "image/jpeg", nil
+				}
+			}
+		}
+		bounds := img.Bounds()
+		longest := max(bounds.Dx(), bounds.Dy())
+		if longest <= 64 {
+			return nil, "", fmt.Errorf("image cannot be reduced below %d bytes", options.maxBytes)
+		}
+		img = scaleImage(img, longest*3/4)
+	}
+}
+
+// isOpaque reports whether every pixel of img is fully opaque
+func isOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// scaleImage resizes img so its longest side is maxDimension, averaging source pixels
+func scaleImage(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width >= height {
+		height = max(1, height*maxDimension/width)
+		width = maxDimension
+	} else {
+		width = max(1, width*maxDimension/height)
+		height = maxDimension
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * bounds.Dy() / height
+		y1 := max(y0+1, (y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := x * bounds.Dx() / width
+			x1 := max(x0+1, (x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					offset := src.PixOffset(sx, sy)
+					r += uint32(src.Pix[offset])
+					g += uint32(src.Pix[offset+1])
+					b += uint32(src.Pix[offset+2])
+					a += uint32(src.Pix[offset+3])
+					n++
+				}
+			}
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+// noise returns a size×size image of random pixels, which compresses poorly
+func noise(size int, alpha uint8) *image.NRGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = uint8(rng.Intn(256))
+		img.Pix[i+1] = uint8(rng.Intn(256))
+		img.Pix[i+2] = uint8(rng.Intn(256))
+		img.Pix[i+3] = alpha
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeImage(t *testing.T, content ImageContent) image.Image {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(content.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestImageFromBytesKeepsFittingImages(t *testing.T) {
+	data := encodePNG(t, noise(16, 255))
+	content, err := ImageFromBytes(data, WithMaxDimension(64), WithMaxBytes(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.MimeType != "image/png" || content.Data != base64.StdEncoding.EncodeToString(data) {
+		t.Errorf("fitting image was re-encoded as %s", content.MimeType)
+	}
+}
+
+func TestImageFromBytesDownscales(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, noise(200, 255), nil); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ImageFromBytes(buf.Bytes(), WithMaxDimension(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.MimeType != "image/jpeg" {
+		t.Errorf("MimeType = %s, want image/jpeg", content.MimeType)
+	}
+	if bounds := decodeImage(t, content).Bounds(); bounds.Dx() != 50 || bounds.Dy() != 50 {
+		t.Errorf("size = %v, want 50x50", bounds.Size())
+	}
+}
+
+func TestImageFromBytesShrinksOpaqueImagesToJPEG(t *testing.T) {
+	data := encodePNG(t, noise(256, 255))
+	content, err := ImageFromBytes(data, WithMaxBytes(len(data)/4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.MimeType != "image/jpeg" {
+		t.Errorf("MimeType = %s, want image/jpeg", content.MimeType)
+	}
+	if content.Size() > len(data)/4 {
+		t.Errorf("size %d exceeds limit %d", content.Size(), len(data)/4)
+	}
+}
+
+func TestImageFromBytesKeepsTransparency(t *testing.T) {
+	data := encodePNG(t, noise(256, 128))
+
+	for name, opts := range map[string][]ImageOption{
+		"max dimension": {WithMaxDimension(100)},
+		"max bytes":     {WithMaxBytes(len(data) / 4)},
+	} {
+		content, err := ImageFromBytes(data, opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if content.MimeType != "image/png" {
+			t.Errorf("%s: MimeType = %s, want image/png", name, content.MimeType)
+		}
+		img := decodeImage(t, content)
+		if c := color.NRGBAModel.Convert(img.At(0, 0)).(color.NRGBA); c.A == 255 {
+			t.Errorf("%s: alpha channel lost", name)
+		}
+	}
+}
+
+func TestImageFromBytesRejectsWebPResize(t *testing.T) {
+	webp := append([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "), make([]byte, 64)...)
+
+	content, err := ImageFromBytes(webp)
+	if err != nil || content.MimeType != "image/webp" {
+		t.Errorf("ImageFromBytes without limits = %s, %v", content.MimeType, err)
+	}
+	if _, err := ImageFromBytes(webp, WithMaxDimension(32)); !errors.Is(err, ErrUnsupportedContent) {
+		t.Errorf("resize error = %v, want ErrUnsupportedContent", err)
+	}
+}
+
+func TestImageFromBytesRejectsNonImages(t *testing.T) {
+	if _, err := ImageFromBytes([]byte("plain text")); !errors.Is(err, ErrUnsupportedContent) {
+		t.Errorf("error = %v, want ErrUnsupportedContent", err)
+	}
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// contentDecoders maps a Content type discriminator to its decoder
+var contentDecoders = map[string]func(json.RawMessage) (Content, error){
+	TextContent{}.Type():     decodeContent[TextContent],
+	ImageContent{}.Type():    decodeContent[ImageContent],
+	ToolCall{}.Type():        decodeContent[ToolCall],
+	JSONContent{}.Type():     decodeContent[JSONContent],
+	DocumentContent{}.Type(): decodeContent[DocumentContent],
+	AudioContent{}.Type():    decodeContent[AudioContent],
+}
+
+func decodeContent[T Content](data json.RawMessage) (Content, error) {
+	var c T
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type contentEnvelope struct {
+	Type    string          `json:"type"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalContent encodes c together with its type so it can be decoded again
+func MarshalContent(c Content) ([]byte, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(contentEnvelope{
+		Type:    c.Type(),
+		Content: data,
+	})
+}
+
+// UnmarshalContent decodes content encoded by MarshalContent
+func UnmarshalContent(data []byte) (Content, error) {
+	var envelope contentEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	decode, ok := contentDecoders[envelope.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown content type %q", envelope.Type)
+	}
+	return decode(envelope.Content)
+}
+
+type assistantMessageJSON struct {
+	Contents     []json.RawMessage `json:"contents"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Provider     ModelProvider     `json:"provider"`
+	ErrorMessage *string           `json:"errorMessage,omitempty"`
+	StopReason   StopReason        `json:"stopReason"`
+	Usage        Usage             `json:"usage"`
+}
+
+func (m AssistantMessage) MarshalJSON() ([]byte, error) {
+	contents := make([]json.RawMessage, 0, len(m.Contents))
+	for _, c := range m.Contents {
+		data, err := MarshalContent(c)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, data)
+	}
+
+	return json.Marshal(assistantMessageJSON{
+		Contents:     contents,
+		Timestamp:    m.Timestamp,
+		Provider:     m.Provider,
+		ErrorMessage: m.ErrorMessage,
+		StopReason:   m.StopReason,
+		Usage:        m.Usage,
+	})
+}
+
+func (m *AssistantMessage) UnmarshalJSON(data []byte) error {
+	var raw assistantMessageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	contents := make([]Content, 0, len(raw.Contents))
+	for _, c := range raw.Contents {
+		content, err := UnmarshalContent(c)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, content)
+	}
+
+	*m = AssistantMessage{
+		Contents:     contents,
+		Timestamp:    raw.Timestamp,
+		Provider:     raw.Provider,
+		ErrorMessage: raw.ErrorMessage,
+		StopReason:   raw.StopReason,
+		Usage:        raw.Usage,
+	}
+	return nil
+}
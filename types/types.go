@@ -9,6 +9,7 @@ const (
 	// Model provider constants
 	ProviderNvidia    ModelProvider = "nvidia"
 	ProviderOpenAI    ModelProvider = "openai"
+	ProviderAzure     ModelProvider = "azure"
 	ProviderAnthropic ModelProvider = "anthropic"
 	ProviderMistral   ModelProvider = "mistral"
 	ProviderCustom    ModelProvider = "custom"
@@ -21,6 +22,18 @@ var (
 	ErrModelNotFound = errors.New("model not found")
 	// ErrConfigInvalid is returned when configuration is invalid
 	ErrConfigInvalid = errors.New("invalid configuration")
+	// ErrInvalidStructuredOutput is returned when a response does not match the requested schema
+	ErrInvalidStructuredOutput = errors.New("invalid structured output")
+	// ErrUnsupportedContent is returned when a model cannot accept a content type
+	ErrUnsupportedContent = errors.New("unsupported content")
+	// ErrContentTooLarge is returned when an attachment exceeds the model limits
+	ErrContentTooLarge = errors.New("content too large")
+	// ErrNotSupported is returned when a provider does not implement an operation
+	ErrNotSupported = errors.New("operation not supported")
+	// ErrCredentials is returned when a credential source cannot supply a key
+	ErrCredentials = errors.New("credentials unavailable")
+	// ErrContentFiltered is returned when a provider's content filter rejects a prompt
+	ErrContentFiltered = errors.New("content filtered")
 )
 
 // Content represents any content that can be part of a message
@@ -40,10 +53,13 @@ func (t TextContent) Type() string {
 
 func (t TextContent) isContent() {}
 
-// ImageContent represents image content
+// ImageContent represents image content. Data holds base64 encoded bytes (or,
+// for backwards compatibility, a complete data URL); URL references a remote image.
 type ImageContent struct {
 	Data     string
 	MimeType string
+	URL      string      `json:",omitempty"`
+	Detail   ImageDetail `json:",omitempty"`
 }
 
 func (i ImageContent) Type() string {
@@ -52,11 +68,41 @@ func (i ImageContent) Type() string {
 
 func (i ImageContent) isContent() {}
 
+// JSONContent represents a structured payload, e.g. a tool result object
+type JSONContent struct {
+	Value any
+}
+
+func (j JSONContent) Type() string {
+	return "json"
+}
+
+func (j JSONContent) isContent() {}
+
+// AudioContent represents audio sent by the user or produced by the model.
+// Data holds base64 encoded audio in Format (e.g. "wav", "mp3", "pcm16").
+type AudioContent struct {
+	Format     string
+	Data       string
+	Transcript string
+	// ID identifies model generated audio so later turns can reference it
+	ID        string    `json:",omitempty"`
+	ExpiresAt time.Time `json:",omitempty"`
+}
+
+func (a AudioContent) Type() string {
+	return "audio"
+}
+
+func (a AudioContent) isContent() {}
+
 // ToolCall represents a function/tool call
 type ToolCall struct {
 	ID        string
 	Name      string
 	Arguments map[string]any
+	// RawArguments is the argument JSON exactly as produced by the model, if known
+	RawArguments string
 }
 
 func (t ToolCall) Type() string {
@@ -95,6 +141,7 @@ type AssistantMessage struct {
 	Provider     ModelProvider
 	ErrorMessage *string
 	StopReason   StopReason
+	Usage        Usage
 }
 
 func (m AssistantMessage) isMessage() {}
@@ -107,14 +154,23 @@ func (m AssistantMessage) Content() []Content {
 	return m.Contents
 }
 
-// ToolMessage represents a response from a tool call
+// Usage reports token consumption for a single request
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+	TotalTokens  int
+}
+
+// ToolMessage represents a response from a tool call. Contents may hold
+// text, images and JSON payloads.
 type ToolMessage struct {
 	ToolCallId string
 	ToolName   string
 	Contents   []Content
-	Details    *any
-	IsError    bool
-	Timestamp  time.Time
+	// Details carries application data about the result; it is never sent to the model
+	Details   *any
+	IsError   bool
+	Timestamp time.Time
 }
 
 func (m ToolMessage) isMessage() {}
@@ -134,12 +190,59 @@ type Tool struct {
 	Parameters  map[string]any
 }
 
+// ResponseFormatType selects how the model formats its answer
+type ResponseFormatType string
+
+const (
+	ResponseFormatText       ResponseFormatType = "text"
+	ResponseFormatJSONObject ResponseFormatType = "json_object"
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema"
+)
+
+// ResponseFormat constrains the model output, e.g. to JSON matching a schema
+type ResponseFormat struct {
+	Type ResponseFormatType
+	// Name, Description, Schema and Strict apply to ResponseFormatJSONSchema
+	Name        string
+	Description string
+	Schema      map[string]any
+	Strict      bool
+}
+
+// AudioOutput requests spoken audio alongside the text response
+type AudioOutput struct {
+	Voice  string
+	Format string
+}
+
 // Context represents the full conversation context
 type Context struct {
-	SystemPrompt string
-	Messages     []Message
-	Tools        []Tool
-	Metadata     map[string]any
+	SystemPrompt   string
+	Messages       []Message
+	Tools          []Tool
+	Metadata       map[string]any
+	ResponseFormat *ResponseFormat
+	Audio          *AudioOutput
+}
+
+// EmbeddingEncoding selects how embeddings are transferred over the wire
+type EmbeddingEncoding string
+
+const (
+	EmbeddingEncodingFloat  EmbeddingEncoding = "float"
+	EmbeddingEncodingBase64 EmbeddingEncoding = "base64"
+)
+
+// EmbedOptions configures an embeddings request
+type EmbedOptions struct {
+	// Dimensions truncates embeddings to this size on models that support it
+	Dimensions int
+	// Encoding defaults to base64, which is smaller on the wire
+	Encoding EmbeddingEncoding
+	// InputType distinguishes "query" and "passage" inputs on retrieval models such as NVIDIA's
+	InputType string
+	// BatchSize overrides the number of inputs sent per request
+	BatchSize int
 }
 
 // Model represents a specific model from a provider
@@ -202,6 +305,8 @@ func (e EventTextEnd) isMessageEvent() {}
 // EventToolcallStart represents the start of a tool call
 type EventToolcallStart struct {
 	ContentIndex int
+	ID           string
+	Name         string
 	Partial      AssistantMessage
 }
 
@@ -210,8 +315,12 @@ func (e EventToolcallStart) isMessageEvent() {}
 // EventToolcallDelta represents incremental tool call updates
 type EventToolcallDelta struct {
 	ContentIndex int
+	ID           string
+	Name         string
 	Delta        string
-	Partial      AssistantMessage
+	// Arguments is a best-effort parse of the argument deltas received so far
+	Arguments map[string]any
+	Partial   AssistantMessage
 }
 
 func (e EventToolcallDelta) isMessageEvent() {}
@@ -225,6 +334,34 @@ type EventToolcallEnd struct {
 
 func (e EventToolcallEnd) isMessageEvent() {}
 
+// EventAudioStart represents the start of audio output
+type EventAudioStart struct {
+	ContentIndex int
+	Partial      AssistantMessage
+}
+
+func (e EventAudioStart) isMessageEvent() {}
+
+// EventAudioDelta represents an incremental audio chunk and/or transcript text
+type EventAudioDelta struct {
+	ContentIndex int
+	// Data is a base64 encoded chunk of audio
+	Data       string
+	Transcript string
+	Partial    AssistantMessage
+}
+
+func (e EventAudioDelta) isMessageEvent() {}
+
+// EventAudioEnd represents the end of audio output
+type EventAudioEnd struct {
+	ContentIndex int
+	Content      AudioContent
+	Partial      AssistantMessage
+}
+
+func (e EventAudioEnd) isMessageEvent() {}
+
 // EventDone represents the completion of a response
 type EventDone struct {
 	Reason  StopReason
@@ -241,7 +378,9 @@ type EventError struct {
 
 func (e EventError) isMessageEvent() {}
 
-// AssistantMessageEventStream manages streaming events
+// AssistantMessageEventStream manages streaming events.
+// Events is closed after EventDone or EventError; Result and Err then each
+// hold a single value (Err is nil on success) before being closed.
 type AssistantMessageEventStream struct {
 	Events chan AssistantMessageEvent
 	Result chan AssistantMessage
@@ -252,8 +391,8 @@ type AssistantMessageEventStream struct {
 func NewAssistantMessageEventStream() AssistantMessageEventStream {
 	return AssistantMessageEventStream{
 		Events: make(chan AssistantMessageEvent),
-		Result: make(chan AssistantMessage),
-		Err:    make(chan error),
+		Result: make(chan AssistantMessage, 1),
+		Err:    make(chan error, 1),
 	}
 }
 